@@ -0,0 +1,342 @@
+package cli
+
+/*
+#include <sqlcli1.h>
+*/
+import "C"
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// arrayParam is a Go slice bound to an ARRAY parameter of a CALL statement.
+// The elements are stored one after another in data, each elemSize bytes,
+// with their lengths or null indicators in ind. card is the number of
+// elements; DB2 CLI sets it to the cardinality of an OUT array.
+type arrayParam struct {
+	idx      int // 1 based
+	ctype    C.SQLSMALLINT
+	elemSize int
+	data     []byte
+	ind      []C.SQLLEN
+	card     C.SQLINTEGER
+	loc      *time.Location
+	// pointer to the slice of an OUT or INOUT array; nil for an IN array
+	dest reflect.Value
+}
+
+// Array binds the Go slice in Slice to an ARRAY parameter of a CALL. A slice
+// isn't bound without the wrapper, so a slice passed by mistake, for example
+// to IN (?), is an error before the statement runs. An OUT or INOUT ARRAY is
+// a sql.Out whose Dest points to a slice.
+type Array struct {
+	Slice interface{}
+}
+
+// isArray reports whether v is a slice that can be bound as an ARRAY.
+// []byte is bound as binary data instead.
+func isArray(v interface{}) bool {
+	t := reflect.TypeOf(v)
+	return t != nil && t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+}
+
+// isArrayDest reports whether v is a pointer to a slice that receives an ARRAY.
+func isArrayDest(v interface{}) bool {
+	t := reflect.TypeOf(v)
+	return t != nil && t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Slice &&
+		t.Elem().Elem().Kind() != reflect.Uint8
+}
+
+// bindArray binds the slice in v, or the slice pointed to by the Dest of
+// a sql.Out, to the ARRAY parameter at idx. The element type comes from
+// SQLDescribeParam, and the buffer of an OUT array holds as many elements
+// as the cardinality of the ARRAY type.
+func bindArray(s *stmt, idx int, v interface{}) (*param, error) {
+	a := &arrayParam{idx: idx + 1, loc: s.conn.opts.location()}
+	var in reflect.Value
+	inputOutputType := C.SQLSMALLINT(C.SQL_PARAM_INPUT)
+	if o, ok := v.(sql.Out); ok {
+		a.dest = reflect.ValueOf(o.Dest)
+		inputOutputType = C.SQL_PARAM_OUTPUT
+		if o.In {
+			inputOutputType = C.SQL_PARAM_INPUT_OUTPUT
+			in = a.dest.Elem()
+		}
+	} else {
+		in = reflect.ValueOf(v)
+	}
+
+	desc, err := s.describeParam(idx)
+	if err != nil {
+		return nil, err
+	}
+	sqltype, size, decimal := desc.sqltype, desc.size, desc.decimalDigits
+	if err := a.setElemType(sqltype, size); err != nil {
+		return nil, err
+	}
+
+	n := 0
+	if in.IsValid() {
+		n = in.Len()
+	}
+	capacity := n
+	if a.dest.IsValid() {
+		if max := s.maxCardinality(idx); max > capacity {
+			capacity = max
+		}
+	}
+	if capacity == 0 {
+		// SQLBindParameter needs a buffer
+		capacity = 1
+	}
+	a.data = make([]byte, capacity*a.elemSize)
+	a.ind = make([]C.SQLLEN, capacity)
+	a.card = C.SQLINTEGER(n)
+	for i := 0; i < n; i++ {
+		if err := a.set(i, in.Index(i)); err != nil {
+			return nil, err
+		}
+	}
+
+	ret := C.SQLBindParameter(C.SQLHSTMT(s.hstmt), C.SQLUSMALLINT(idx+1),
+		inputOutputType, a.ctype, sqltype, size, decimal,
+		C.SQLPOINTER(unsafe.Pointer(&a.data[0])), C.SQLLEN(a.elemSize), &a.ind[0])
+	if !success(ret) {
+		return nil, formatError(C.SQL_HANDLE_STMT, s.hstmt)
+	}
+	// The IPD holds the maximum cardinality and the APD points to
+	// the actual cardinality.
+	var ipd, apd C.SQLHDESC
+	ret = C.SQLGetStmtAttr(C.SQLHSTMT(s.hstmt), C.SQL_ATTR_IMP_PARAM_DESC,
+		C.SQLPOINTER(unsafe.Pointer(&ipd)), 0, nil)
+	if !success(ret) {
+		return nil, formatError(C.SQL_HANDLE_STMT, s.hstmt)
+	}
+	ret = C.SQLSetDescField(ipd, C.SQLSMALLINT(idx+1), C.SQL_DESC_CARDINALITY,
+		C.SQLPOINTER(uintptr(capacity)), 0)
+	if !success(ret) {
+		return nil, formatError(C.SQL_HANDLE_DESC, C.SQLHANDLE(ipd))
+	}
+	ret = C.SQLGetStmtAttr(C.SQLHSTMT(s.hstmt), C.SQL_ATTR_APP_PARAM_DESC,
+		C.SQLPOINTER(unsafe.Pointer(&apd)), 0, nil)
+	if !success(ret) {
+		return nil, formatError(C.SQL_HANDLE_STMT, s.hstmt)
+	}
+	ret = C.SQLSetDescField(apd, C.SQLSMALLINT(idx+1), C.SQL_DESC_CARDINALITY_PTR,
+		C.SQLPOINTER(unsafe.Pointer(&a.card)), 0)
+	if !success(ret) {
+		return nil, formatError(C.SQL_HANDLE_DESC, C.SQLHANDLE(apd))
+	}
+	return &param{buf: unsafe.Pointer(&a.data[0]), plen: &a.ind[0], array: a}, nil
+}
+
+// maxCardinality returns the maximum cardinality of the ARRAY parameter
+// at idx, or 0 if DB2 CLI doesn't report it.
+func (s *stmt) maxCardinality(idx int) int {
+	var ipd C.SQLHDESC
+	ret := C.SQLGetStmtAttr(C.SQLHSTMT(s.hstmt), C.SQL_ATTR_IMP_PARAM_DESC,
+		C.SQLPOINTER(unsafe.Pointer(&ipd)), 0, nil)
+	if !success(ret) {
+		return 0
+	}
+	var card C.SQLINTEGER
+	ret = C.SQLGetDescField(ipd, C.SQLSMALLINT(idx+1), C.SQL_DESC_CARDINALITY,
+		C.SQLPOINTER(unsafe.Pointer(&card)), 0, nil)
+	if !success(ret) {
+		return 0
+	}
+	return int(card)
+}
+
+// setElemType picks the C type and buffer size of the array elements
+// from the SQL type of the ARRAY elements.
+func (a *arrayParam) setElemType(sqltype C.SQLSMALLINT, size C.SQLULEN) error {
+	switch sqltype {
+	case C.SQL_SMALLINT, C.SQL_INTEGER, C.SQL_BIGINT:
+		a.ctype, a.elemSize = C.SQL_C_SBIGINT, 8
+	case C.SQL_DECIMAL, C.SQL_NUMERIC:
+		// a string keeps the digits that a double can't hold;
+		// the digits, sign, decimal point and null terminator
+		a.ctype, a.elemSize = C.SQL_C_CHAR, int(size)+3
+	case C.SQL_DECFLOAT:
+		// 34 digits with sign, decimal point, exponent and null terminator
+		a.ctype, a.elemSize = C.SQL_C_CHAR, 43
+	case C.SQL_REAL, C.SQL_FLOAT, C.SQL_DOUBLE:
+		a.ctype, a.elemSize = C.SQL_C_DOUBLE, 8
+	case C.SQL_BIT, C.SQL_BOOLEAN:
+		a.ctype, a.elemSize = C.SQL_C_BIT, 1
+	case C.SQL_CHAR, C.SQL_VARCHAR, C.SQL_WCHAR, C.SQL_WVARCHAR,
+		C.SQL_GRAPHIC, C.SQL_VARGRAPHIC:
+		// utf16 with a null terminator
+		a.ctype, a.elemSize = C.SQL_C_WCHAR, (int(size)+1)*2
+	case C.SQL_TYPE_DATE:
+		var v sql_DATE_STRUCT
+		a.ctype, a.elemSize = C.SQL_C_TYPE_DATE, int(unsafe.Sizeof(v))
+	case C.SQL_TYPE_TIMESTAMP:
+		var v sql_TIMESTAMP_STRUCT
+		a.ctype, a.elemSize = C.SQL_C_TYPE_TIMESTAMP, int(unsafe.Sizeof(v))
+	default:
+		return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: unsupported ARRAY element type %d at param. index %d",
+			sqltype, a.idx)
+	}
+	return nil
+}
+
+// set stores the Go value v as element i.
+func (a *arrayParam) set(i int, v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			a.ind[i] = C.SQL_NULL_DATA
+			return nil
+		}
+		v = v.Elem()
+	}
+	b := a.data[i*a.elemSize : (i+1)*a.elemSize]
+	p := unsafe.Pointer(&b[0])
+	a.ind[i] = C.SQLLEN(a.elemSize)
+	switch a.ctype {
+	case C.SQL_C_SBIGINT:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			*(*int64)(p) = v.Int()
+			return nil
+		case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if v.Uint() > math.MaxInt64 {
+				return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: ARRAY element %d at param. index %d: %d overflows BIGINT",
+					i, a.idx, v.Uint())
+			}
+			*(*int64)(p) = int64(v.Uint())
+			return nil
+		}
+	case C.SQL_C_DOUBLE:
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			*(*float64)(p) = v.Float()
+			return nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			*(*float64)(p) = float64(v.Int())
+			return nil
+		}
+	case C.SQL_C_CHAR:
+		var s string
+		switch v.Kind() {
+		case reflect.String:
+			s = v.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			s = strconv.FormatInt(v.Int(), 10)
+		case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			s = strconv.FormatUint(v.Uint(), 10)
+		case reflect.Float32, reflect.Float64:
+			s = strconv.FormatFloat(v.Float(), 'f', -1, 64)
+		default:
+			return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: can't bind %s as ARRAY element at param. index %d",
+				v.Type(), a.idx)
+		}
+		if len(s) >= len(b) {
+			return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: ARRAY element %d at param. index %d is longer than the element type",
+				i, a.idx)
+		}
+		copy(b, s)
+		a.ind[i] = C.SQL_NTS
+		return nil
+	case C.SQL_C_BIT:
+		if v.Kind() == reflect.Bool {
+			if v.Bool() {
+				b[0] = 1
+			}
+			return nil
+		}
+	case C.SQL_C_WCHAR:
+		if v.Kind() == reflect.String {
+			s := extractUTF16Str(stringToUTF16(v.String()))
+			if len(s) > len(b) {
+				return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: ARRAY element %d at param. index %d is longer than the element type",
+					i, a.idx)
+			}
+			copy(b, s)
+			a.ind[i] = C.SQL_NTS
+			return nil
+		}
+	case C.SQL_C_TYPE_DATE, C.SQL_C_TYPE_TIMESTAMP:
+		t, ok := v.Interface().(time.Time)
+		if !ok {
+			break
+		}
+		t = t.In(a.loc)
+		if a.ctype == C.SQL_C_TYPE_DATE {
+			y, m, d := t.Date()
+			*(*sql_DATE_STRUCT)(p) = sql_DATE_STRUCT{year: C.SQLSMALLINT(y), month: C.SQLUSMALLINT(m), day: C.SQLUSMALLINT(d)}
+			return nil
+		}
+		*(*sql_TIMESTAMP_STRUCT)(p) = timestampStruct(t, 9)
+		return nil
+	}
+	return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: can't bind %s as ARRAY element at param. index %d",
+		v.Type(), a.idx)
+}
+
+// value returns element i as a driver.Value.
+func (a *arrayParam) value(i int) driver.Value {
+	if a.ind[i] == C.SQL_NULL_DATA {
+		return nil
+	}
+	b := a.data[i*a.elemSize : (i+1)*a.elemSize]
+	p := unsafe.Pointer(&b[0])
+	switch a.ctype {
+	case C.SQL_C_SBIGINT:
+		return *(*int64)(p)
+	case C.SQL_C_DOUBLE:
+		return *(*float64)(p)
+	case C.SQL_C_BIT:
+		return b[0] != 0
+	case C.SQL_C_CHAR:
+		// DECIMAL and DECFLOAT are returned as a string
+		// to keep their precision, as for OUT parameters
+		n := bytes.IndexByte(b, 0)
+		if n < 0 {
+			n = len(b)
+		}
+		return strings.TrimSpace(string(b[:n]))
+	case C.SQL_C_WCHAR:
+		n := len(b) / 2
+		if a.ind[i] >= 0 && int(a.ind[i])/2 < n {
+			n = int(a.ind[i]) / 2
+		}
+		return utf16ToString((*[1 << 28]uint16)(p)[:n:n])
+	case C.SQL_C_TYPE_DATE:
+		t := (*sql_DATE_STRUCT)(p)
+		return time.Date(int(t.year), time.Month(t.month), int(t.day), 0, 0, 0, 0, a.loc)
+	case C.SQL_C_TYPE_TIMESTAMP:
+		t := (*sql_TIMESTAMP_STRUCT)(p)
+		return time.Date(int(t.year), time.Month(t.month), int(t.day),
+			int(t.hour), int(t.minute), int(t.second), int(t.fraction), a.loc)
+	}
+	return nil
+}
+
+// assign sets the slice pointed to by dest to the elements of an OUT array.
+func (a *arrayParam) assign() error {
+	n := int(a.card)
+	if n < 0 || n > len(a.ind) {
+		return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: invalid cardinality %d of OUT ARRAY at param. index %d",
+			n, a.idx)
+	}
+	slice := reflect.MakeSlice(a.dest.Elem().Type(), n, n)
+	for i := 0; i < n; i++ {
+		err := convertAssignIn(slice.Index(i).Addr().Interface(), a.value(i), a.loc)
+		if err != nil {
+			return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: OUT ARRAY element %d at param. index %d: %v",
+				i, a.idx, err)
+		}
+	}
+	a.dest.Elem().Set(slice)
+	return nil
+}
@@ -0,0 +1,399 @@
+package cli
+
+/*
+#include <sqlcli1.h>
+*/
+import "C"
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// CallResult holds the OUT and INOUT parameter values and the result sets
+// of a procedure called with Call.
+type CallResult struct {
+	// Out holds the OUT and INOUT parameter values by parameter name
+	// as stored in the DB2 catalog, usually upper case.
+	Out        map[string]interface{}
+	ResultSets []ResultSet
+	loc        *time.Location // Location driver option for Scan
+}
+
+// Scan copies the value of the OUT or INOUT parameter name, ignoring case,
+// to dest, converting it like Rows.Scan does.
+func (r *CallResult) Scan(name string, dest interface{}) error {
+	v, ok := r.Out[strings.ToUpper(name)]
+	if !ok {
+		return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: no OUT parameter %s", name)
+	}
+	if err := convertAssignIn(dest, v, r.loc); err != nil {
+		return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: OUT parameter %s: %v", name, err)
+	}
+	return nil
+}
+
+// Int64 returns the value of the OUT or INOUT parameter name as an int64.
+func (r *CallResult) Int64(name string) (int64, error) {
+	var v int64
+	err := r.Scan(name, &v)
+	return v, err
+}
+
+// String returns the value of the OUT or INOUT parameter name as a string.
+func (r *CallResult) String(name string) (string, error) {
+	var v string
+	err := r.Scan(name, &v)
+	return v, err
+}
+
+// Time returns the value of the OUT or INOUT parameter name as a time.Time.
+func (r *CallResult) Time(name string) (time.Time, error) {
+	var v time.Time
+	err := r.Scan(name, &v)
+	return v, err
+}
+
+// ResultSet is a result set read into memory.
+type ResultSet struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// procParam is a parameter of a procedure from SQLProcedureColumns.
+type procParam struct {
+	name      string
+	direction ParamDirection
+}
+
+// Call calls the stored procedure proc, which can be qualified with its schema.
+// An unqualified procedure is looked up in the schemas of CURRENT PATH, in
+// order, as DB2 resolves it. The parameters are looked up with
+// SQLProcedureColumns and cached on the connection; the cache entry is dropped
+// when the CALL fails because the procedure was dropped or changed. The IN and INOUT values are taken from args, a map with
+// string keys or a struct, by parameter name ignoring case. A struct field
+// is matched by its name or its `cli:"name"` tag. A parameter without a value
+// is passed as null.
+//
+//	res, err := cli.Call(ctx, conn, "hr.raise_salary", map[string]interface{}{"id": 10, "pct": 5})
+//	...
+//	newSalary, err := res.Int64("new_salary")
+func Call(ctx context.Context, dbConn *sql.Conn, proc string, args interface{}) (*CallResult, error) {
+	values, err := callArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	var res *CallResult
+	err = dbConn.Raw(func(dc interface{}) error {
+		c, ok := dc.(*conn)
+		if !ok {
+			return errors.New("database/sql/driver: [asifjalil][CLI Driver]: Call needs a cli connection")
+		}
+		res, err = c.call(ctx, proc, values)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// callArgs returns the values in args by upper case name.
+func callArgs(args interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if args == nil {
+		return values, nil
+	}
+	v := reflect.ValueOf(args)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		for _, k := range v.MapKeys() {
+			values[strings.ToUpper(k.String())] = v.MapIndex(k).Interface()
+		}
+	case v.Kind() == reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				// unexported
+				continue
+			}
+			name := f.Name
+			if tag := f.Tag.Get("cli"); tag != "" {
+				name = tag
+			}
+			values[strings.ToUpper(name)] = v.Field(i).Interface()
+		}
+	default:
+		return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: Call arguments must be a map or a struct, not %T", args)
+	}
+	return values, nil
+}
+
+// procParams returns the parameters of proc in order.
+func (c *conn) procParams(proc string) ([]procParam, error) {
+	key := strings.ToUpper(proc)
+	if params, ok := c.procs[key]; ok {
+		return params, nil
+	}
+
+	var schema, name string
+	if i := strings.LastIndexByte(proc, '.'); i >= 0 {
+		schema = escapePattern(catalogName(proc[:i]))
+		name = escapePattern(catalogName(proc[i+1:]))
+	} else {
+		name = escapePattern(catalogName(proc))
+		var err error
+		schema, err = c.procSchema(proc, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, data, err := c.catalogQuery(func(h C.SQLHSTMT) C.SQLRETURN {
+		s, sl := catalogArg(schema)
+		p, pl := catalogArg(name)
+		return C.SQLProcedureColumnsW(h, nil, 0, s, sl, p, pl, nil, 0)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		// a procedure without parameters has no rows
+		_, data, err = c.catalogQuery(func(h C.SQLHSTMT) C.SQLRETURN {
+			s, sl := catalogArg(schema)
+			p, pl := catalogArg(name)
+			return C.SQLProceduresW(h, nil, 0, s, sl, p, pl)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 {
+			return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: procedure %s not found", proc)
+		}
+		if len(data) > 1 {
+			return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: procedure %s is overloaded or in more than one schema; Call needs a unique procedure", proc)
+		}
+	}
+
+	var params []procParam
+	seen := make(map[int64]bool)
+	for _, row := range data {
+		// COLUMN_NAME is column 4, COLUMN_TYPE 5 and ORDINAL_POSITION 18
+		direction := ParamDirection(asInt64(row[4]))
+		switch direction {
+		case ParamInput, ParamInputOutput, ParamOutput:
+		default:
+			// result set columns and return values
+			continue
+		}
+		pos := asInt64(row[17])
+		if seen[pos] {
+			return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: procedure %s is overloaded or in more than one schema; Call needs a unique procedure", proc)
+		}
+		seen[pos] = true
+		colName, _ := row[3].(string)
+		params = append(params, procParam{name: colName, direction: direction})
+	}
+	if c.procs == nil {
+		c.procs = make(map[string][]procParam)
+	}
+	c.procs[key] = params
+	return params, nil
+}
+
+// procSchema returns the escaped schema of the unqualified procedure name:
+// the first schema in CURRENT PATH that has a procedure with that name.
+func (c *conn) procSchema(proc, name string) (string, error) {
+	_, data, err := c.catalogQuery(func(h C.SQLHSTMT) C.SQLRETURN {
+		return C.SQLExecDirectW(h,
+			(*C.SQLWCHAR)(unsafe.Pointer(stringToUTF16Ptr("VALUES CURRENT PATH"))), C.SQL_NTS)
+	})
+	if err != nil {
+		return "", err
+	}
+	var path string
+	if len(data) == 1 {
+		path, _ = data[0][0].(string)
+	}
+	for _, schema := range splitPath(path) {
+		schema = escapePattern(schema)
+		_, procs, err := c.catalogQuery(func(h C.SQLHSTMT) C.SQLRETURN {
+			s, sl := catalogArg(schema)
+			p, pl := catalogArg(name)
+			return C.SQLProceduresW(h, nil, 0, s, sl, p, pl)
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(procs) > 0 {
+			return schema, nil
+		}
+	}
+	return "", fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: procedure %s not found in CURRENT PATH %s", proc, path)
+}
+
+// splitPath returns the schema names in a CURRENT PATH value
+// such as "SYSIBM","SYSFUN","SYSPROC","ME".
+func splitPath(path string) []string {
+	var schemas []string
+	var b strings.Builder
+	quoted := false
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c == '"' && quoted && i+1 < len(path) && path[i+1] == '"':
+			// an escaped quote
+			b.WriteByte(c)
+			i++
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			schemas = append(schemas, b.String())
+			b.Reset()
+		case c == ' ' && !quoted:
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if b.Len() > 0 {
+		schemas = append(schemas, b.String())
+	}
+	return schemas
+}
+
+// isStaleProc reports whether err means the procedure of a CALL was dropped
+// or changed: SQL0440N for no procedure with the name and number of
+// arguments, and SQL0469N or SQL0204N after the parameters changed.
+func isStaleProc(err error) bool {
+	if e, ok := err.(*cliError); ok {
+		switch e.sqlcode {
+		case -440, -469, -204:
+			return true
+		}
+	}
+	return false
+}
+
+// catalogName returns the catalog form of an identifier: a delimited
+// identifier without its quotes, or an ordinary identifier in upper case.
+func catalogName(id string) string {
+	id = strings.TrimSpace(id)
+	if len(id) >= 2 && id[0] == '"' && id[len(id)-1] == '"' {
+		return strings.Replace(id[1:len(id)-1], `""`, `"`, -1)
+	}
+	return strings.ToUpper(id)
+}
+
+func asInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	}
+	return 0
+}
+
+// call runs CALL proc with values and reads all its result sets.
+func (c *conn) call(ctx context.Context, proc string, values map[string]interface{}) (*CallResult, error) {
+	params, err := c.procParams(proc)
+	if err != nil {
+		return nil, err
+	}
+	for name := range values {
+		found := false
+		for _, p := range params {
+			if strings.ToUpper(p.name) == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: procedure %s has no parameter %s", proc, name)
+		}
+	}
+
+	markers := make([]string, len(params))
+	for i := range markers {
+		markers[i] = "?"
+	}
+	ds, err := c.PrepareContext(ctx, "CALL "+proc+"("+strings.Join(markers, ", ")+")")
+	if err != nil {
+		if isStaleProc(err) {
+			delete(c.procs, strings.ToUpper(proc))
+		}
+		return nil, err
+	}
+	s := ds.(*stmt)
+	defer s.Close()
+
+	args := make([]driver.Value, len(params))
+	outs := make(map[int]reflect.Value)
+	for i, p := range params {
+		v := values[strings.ToUpper(p.name)]
+		if p.direction == ParamInput {
+			nv := driver.NamedValue{Ordinal: i + 1, Value: v}
+			if err := s.CheckNamedValue(&nv); err != nil {
+				return nil, err
+			}
+			args[i] = nv.Value
+			continue
+		}
+		// the Dest of an OUT parameter holds the value of an INOUT parameter
+		dest := reflect.New(reflect.TypeOf((*interface{})(nil)).Elem())
+		if v != nil {
+			dest = reflect.New(reflect.TypeOf(v))
+			dest.Elem().Set(reflect.ValueOf(v))
+		}
+		args[i] = sql.Out{Dest: dest.Interface(), In: p.direction == ParamInputOutput}
+		outs[i] = dest
+	}
+
+	dr, err := s.query(ctx, args)
+	if err != nil {
+		if isStaleProc(err) {
+			delete(c.procs, strings.ToUpper(proc))
+		}
+		return nil, err
+	}
+	r := dr.(*rows)
+	res := &CallResult{Out: make(map[string]interface{}), loc: c.opts.location()}
+	for len(r.s.cols) > 0 {
+		names, data, err := readRows(r)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		res.ResultSets = append(res.ResultSets, ResultSet{Columns: names, Rows: data})
+		if !r.HasNextResultSet() {
+			break
+		}
+		err = r.NextResultSet()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+	}
+	// Close assigns the OUT parameters if a result set is left
+	if err := r.Close(); err != nil {
+		return nil, err
+	}
+	for i, dest := range outs {
+		res.Out[params[i].name] = dest.Elem().Interface()
+	}
+	return res, nil
+}
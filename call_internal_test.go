@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{`"SYSIBM","SYSFUN","SYSPROC","SYSIBMADM","ME"`, []string{"SYSIBM", "SYSFUN", "SYSPROC", "SYSIBMADM", "ME"}},
+		{`"SYSIBM", "My ""Path"", too"`, []string{"SYSIBM", `My "Path", too`}},
+		{"", nil},
+	}
+	for _, tc := range tests {
+		if got := splitPath(tc.path); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: wanted %q, got %q", tc.path, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package cli
+
+/*
+#include <sqlcli1.h>
+*/
+import "C"
+import (
+	"database/sql/driver"
+	"io"
+	"strings"
+	"unsafe"
+)
+
+// catalogQuery runs the DB2 CLI catalog function in call on a new statement
+// handle and returns the column names and rows of its result set.
+func (c *conn) catalogQuery(call func(h C.SQLHSTMT) C.SQLRETURN) ([]string, [][]interface{}, error) {
+	var h C.SQLHANDLE
+	ret := C.SQLAllocHandle(C.SQL_HANDLE_STMT, c.hdbc, &h)
+	if !success(ret) {
+		return nil, nil, formatError(C.SQL_HANDLE_DBC, c.hdbc)
+	}
+	s := &stmt{conn: c, hstmt: h, sql: "CATALOG"}
+	defer s.Close()
+
+	ret = call(C.SQLHSTMT(h))
+	if !success(ret) {
+		return nil, nil, formatError(C.SQL_HANDLE_STMT, h)
+	}
+	names, data, err := s.readResultSet()
+	if err != nil {
+		return nil, nil, err
+	}
+	ret = C.SQLFreeStmt(C.SQLHSTMT(h), C.SQL_CLOSE)
+	if !success(ret) {
+		return nil, nil, formatError(C.SQL_HANDLE_STMT, h)
+	}
+	return names, data, nil
+}
+
+// readResultSet binds the columns of the current result set of s and
+// reads all its rows.
+func (s *stmt) readResultSet() ([]string, [][]interface{}, error) {
+	err := s.bindColumns()
+	if err != nil {
+		return nil, nil, err
+	}
+	return readRows(&rows{s: s})
+}
+
+// readRows reads the rows of the current result set of r.
+func readRows(r *rows) ([]string, [][]interface{}, error) {
+	names := r.Columns()
+	var data [][]interface{}
+	for {
+		row := make([]driver.Value, len(names))
+		err := r.Next(row)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		values := make([]interface{}, len(row))
+		for i, v := range row {
+			// binary values share the column buffer
+			if b, ok := v.([]byte); ok {
+				v = cloneBytes(b)
+			}
+			values[i] = v
+		}
+		data = append(data, values)
+	}
+	return names, data, nil
+}
+
+// catalogArg returns the argument for a string or pattern argument of a
+// DB2 CLI catalog function. An empty string is passed as NULL.
+func catalogArg(s string) (*C.SQLWCHAR, C.SQLSMALLINT) {
+	if s == "" {
+		return nil, 0
+	}
+	return (*C.SQLWCHAR)(unsafe.Pointer(stringToUTF16Ptr(s))), C.SQL_NTS
+}
+
+// escapePattern escapes the wildcards _ and % in name so a catalog
+// function matches it exactly.
+func escapePattern(name string) string {
+	r := strings.NewReplacer(`\`, `\\`, "_", `\_`, "%", `\%`)
+	return r.Replace(name)
+}
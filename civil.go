@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// Date is a DATE value. It has no time of day or time zone.
+// A Date parameter is bound as DATE.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the Date of t in t's location.
+func DateOf(t time.Time) Date {
+	var d Date
+	d.Year, d.Month, d.Day = t.Date()
+	return d
+}
+
+// String returns d in the ISO format yyyy-mm-dd.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// In returns the time.Time at midnight of d in loc.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// Value implements driver.Valuer.
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements sql.Scanner. It accepts a Date, a time.Time,
+// or a string or []byte in the format yyyy-mm-dd.
+func (d *Date) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case Date:
+		*d = v
+		return nil
+	case time.Time:
+		*d = DateOf(v)
+		return nil
+	case []byte:
+		return d.parse(string(v))
+	case string:
+		return d.parse(v)
+	}
+	return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: cannot scan %T into cli.Date", src)
+}
+
+func (d *Date) parse(s string) error {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: invalid date %q", s)
+	}
+	*d = DateOf(t)
+	return nil
+}
+
+// TimeOfDay is a TIME value. It has no date or time zone.
+// A TimeOfDay parameter is bound as TIME. DB2 TIME has no fractional
+// seconds, so Nanosecond is only set when a TimeOfDay is scanned
+// from a TIMESTAMP.
+type TimeOfDay struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// TimeOfDayOf returns the TimeOfDay of t in t's location.
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	return TimeOfDay{
+		Hour:       t.Hour(),
+		Minute:     t.Minute(),
+		Second:     t.Second(),
+		Nanosecond: t.Nanosecond(),
+	}
+}
+
+// String returns t in the format hh:mm:ss[.fffffffff].
+func (t TimeOfDay) String() string {
+	s := fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+	if t.Nanosecond != 0 {
+		s += fmt.Sprintf(".%09d", t.Nanosecond)
+	}
+	return s
+}
+
+// Value implements driver.Valuer.
+func (t TimeOfDay) Value() (driver.Value, error) {
+	return fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second), nil
+}
+
+// Scan implements sql.Scanner. It accepts a TimeOfDay, a time.Time,
+// or a string or []byte in the format hh:mm:ss or hh.mm.ss.
+func (t *TimeOfDay) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case TimeOfDay:
+		*t = v
+		return nil
+	case time.Time:
+		*t = TimeOfDayOf(v)
+		return nil
+	case []byte:
+		return t.parse(string(v))
+	case string:
+		return t.parse(v)
+	}
+	return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: cannot scan %T into cli.TimeOfDay", src)
+}
+
+func (t *TimeOfDay) parse(s string) error {
+	v, err := time.Parse("15:04:05.999999999", s)
+	if err != nil {
+		// DB2 ISO format
+		v, err = time.Parse("15.04.05", s)
+	}
+	if err != nil {
+		return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: invalid time %q", s)
+	}
+	*t = TimeOfDayOf(v)
+	return nil
+}
@@ -9,11 +9,13 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 	"unsafe"
 )
 
 type column struct {
+	opts             *Connector // driver options of the connection
 	h                C.SQLHSTMT
 	idx              int    // column position; starts from 0
 	name             string // column name
@@ -23,8 +25,32 @@ type column struct {
 	scale            int64  // scale of column
 	sqltype          C.SQLSMALLINT
 	ctype            C.SQLSMALLINT
-	data             []byte   // data returned from the database
-	len              C.SQLLEN // StrLen_or_IndPtr; Indicates the size of the data fetched into data
+	data             []byte      // data returned from the database
+	len              C.SQLLEN    // StrLen_or_IndPtr; Indicates the size of the data fetched into data
+	info             *ColumnInfo // filled by columnInfo on first use
+}
+
+// ColumnInfo describes a result set column with the attributes DB2 CLI
+// returns from SQLColAttribute. Attributes the server doesn't return
+// are left empty. TypeName is the name ColumnTypeDatabaseTypeName returns:
+// the driver's name of a built-in type, for example CHARACTER, or the
+// SQL_DESC_TYPE_NAME DB2 reports for a type the driver has no name for.
+type ColumnInfo struct {
+	Name           string // column name
+	Label          string // column label; the name if the column has no label
+	TypeName       string // data type name, for example CHARACTER
+	DistinctType   string // distinct type name if the column is a user-defined distinct type
+	SchemaName     string // schema of the table the column belongs to
+	TableName      string // table the column belongs to
+	BaseTableName  string // base table of the column in a view or derived table
+	BaseColumnName string // column name in the base table
+	AutoIncrement  bool   // true for an identity column
+	Updatable      bool   // false if the column is read-only or its updatability is unknown
+	DisplaySize    int64  // maximum number of characters to display the value
+	OctetLength    int64  // maximum length of the value in bytes
+	Nullable       bool   // true if the column value can be null
+	Precision      int64  // precision of a numeric column; length of other columns
+	Scale          int64  // scale of a numeric column
 }
 
 func (c *column) getData() ([]byte, error) {
@@ -68,8 +94,9 @@ loop:
 				return nil, err
 			}
 			// buf is not big enough; data has been truncated
-			// save the partial data
-			total = append(total, buf...)
+			// save the partial data without the null terminator
+			// that ends character data
+			total = append(total, buf[:len(buf)-nullTermSize(c.ctype)]...)
 		default:
 			return nil, formatError(C.SQL_HANDLE_STMT, C.SQLHANDLE(c.h))
 		}
@@ -79,10 +106,49 @@ loop:
 	return total, nil
 }
 
-func (c *column) typeName() string {
+// nullTermSize returns the size of the null terminator that DB2 CLI
+// adds to character data of C type ctype.
+func nullTermSize(ctype C.SQLSMALLINT) int {
+	switch ctype {
+	case C.SQL_C_CHAR:
+		return 1
+	case C.SQL_C_WCHAR:
+		return 2
+	}
+	return 0
+}
+
+// trimPadding removes the trailing blanks of a fixed-length CHAR or GRAPHIC
+// value if the TrimCharPadding driver option is set.
+func (c *column) trimPadding(s string) string {
+	if !c.opts.TrimCharPadding {
+		return s
+	}
 	switch c.sqltype {
+	case C.SQL_CHAR, C.SQL_WCHAR:
+		return strings.TrimRight(s, " ")
+	case C.SQL_GRAPHIC:
+		// A non-Unicode database pads GRAPHIC with the
+		// double-byte blank U+3000.
+		return strings.TrimRight(s, " \u3000")
+	}
+	return s
+}
+
+func (c *column) typeName() string {
+	if name := sqlTypeName(c.sqltype); name != "UNKNOWN" {
+		return name
+	}
+	return c.columnInfo().TypeName
+}
+
+// sqlTypeName returns the DB2 data type name of sqltype.
+func sqlTypeName(sqltype C.SQLSMALLINT) string {
+	switch sqltype {
 	case C.SQL_BIT:
 		return "BIT"
+	case C.SQL_BOOLEAN:
+		return "BOOLEAN"
 	case C.SQL_TINYINT, C.SQL_SMALLINT:
 		return "SMALLINT"
 	case C.SQL_INTEGER:
@@ -101,6 +167,8 @@ func (c *column) typeName() string {
 		return "REAL"
 	case C.SQL_TYPE_TIMESTAMP:
 		return "TIMESTAMP"
+	case C.SQL_TYPE_TIMESTAMP_WITH_TIMEZONE:
+		return "TIMESTAMP WITH TIME ZONE"
 	case C.SQL_TYPE_DATE:
 		return "DATE"
 	case C.SQL_TYPE_TIME:
@@ -109,6 +177,14 @@ func (c *column) typeName() string {
 		return "CHARACTER"
 	case C.SQL_VARCHAR, C.SQL_WVARCHAR:
 		return "VARCHAR"
+	case C.SQL_GRAPHIC:
+		return "GRAPHIC"
+	case C.SQL_VARGRAPHIC:
+		return "VARGRAPHIC"
+	case C.SQL_LONGVARGRAPHIC:
+		return "LONG VARGRAPHIC"
+	case C.SQL_DBCLOB:
+		return "DBCLOB"
 	case C.SQL_CLOB:
 		return "CLOB"
 	case C.SQL_BLOB:
@@ -137,9 +213,13 @@ func (c *column) typePrecisionScale() (precision, scale int64, ok bool) {
 func (c *column) typeLength() (length int64, ok bool) {
 	switch c.sqltype {
 	case C.SQL_VARCHAR, C.SQL_WVARCHAR, C.SQL_CLOB, C.SQL_BLOB,
-		C.SQL_VARBINARY, C.SQL_XML:
+		C.SQL_VARBINARY, C.SQL_XML,
+		C.SQL_VARGRAPHIC, C.SQL_LONGVARGRAPHIC, C.SQL_DBCLOB:
 		ok = true
 	}
+	if n := c.columnInfo().OctetLength; n > 0 {
+		return n, ok
+	}
 	return c.size, ok
 }
 
@@ -160,8 +240,20 @@ func (c *column) scanType() reflect.Type {
 			return reflect.TypeOf(float64(0.0))
 		}
 		return reflect.TypeOf(string(""))
-	case C.SQL_C_TYPE_DATE, C.SQL_C_TYPE_TIME, C.SQL_C_TYPE_TIMESTAMP:
+	case C.SQL_C_TYPE_DATE:
+		if c.opts.CivilDateTime {
+			return reflect.TypeOf(Date{})
+		}
+		return reflect.TypeOf(time.Time{})
+	case C.SQL_C_TYPE_TIME:
+		if c.opts.CivilDateTime {
+			return reflect.TypeOf(TimeOfDay{})
+		}
 		return reflect.TypeOf(time.Time{})
+	case C.SQL_C_TYPE_TIMESTAMP, C.SQL_C_TYPE_TIMESTAMP_EXT_TZ:
+		return reflect.TypeOf(time.Time{})
+	case C.SQL_C_TYPE_TIMESTAMP_EXT:
+		return reflect.TypeOf(ExtTimestamp{})
 	case C.SQL_C_BINARY:
 		return reflect.TypeOf([]byte(nil))
 	default:
@@ -187,6 +279,13 @@ func (c *column) value() (driver.Value, error) {
 	if c.len == C.SQL_NULL_DATA {
 		return nil, nil
 	}
+	// empty LOB value from SQLGetData
+	if len(buf) == 0 {
+		if nullTermSize(c.ctype) > 0 {
+			return "", nil
+		}
+		return []byte{}, nil
+	}
 	p = unsafe.Pointer(&buf[0])
 
 	switch c.ctype {
@@ -205,13 +304,18 @@ func (c *column) value() (driver.Value, error) {
 			f, err := strconv.ParseFloat(s, 64)
 			return f, err
 		}
-		return buf[:c.len], nil
+		return c.trimPadding(string(buf[:c.len])), nil
 	case C.SQL_C_WCHAR, C.SQL_C_DBCHAR:
 		if p == nil {
 			return nil, nil
 		}
-		s := (*[1 << 20]uint16)(p)[:len(buf)/2]
-		return utf16ToUTF8(s), nil
+		// c.len is the data length in bytes without the null terminator
+		n := len(buf)
+		if c.len >= 0 && int(c.len) < n {
+			n = int(c.len)
+		}
+		s := (*[1 << 28]uint16)(p)[: n/2 : n/2]
+		return c.trimPadding(string(utf16ToUTF8(s))), nil
 	case C.SQL_C_TYPE_TIMESTAMP:
 		t := (*sql_TIMESTAMP_STRUCT)(p)
 		r := time.Date(int(t.year),
@@ -221,23 +325,44 @@ func (c *column) value() (driver.Value, error) {
 			int(t.minute),
 			int(t.second),
 			int(t.fraction),
-			time.Local)
+			c.opts.location())
 		return r, nil
+	case C.SQL_C_TYPE_TIMESTAMP_EXT:
+		// picoseconds in fraction2 don't fit in time.Time
+		t := (*sql_TIMESTAMP_STRUCT_EXT)(p)
+		r := time.Date(int(t.year),
+			time.Month(t.month),
+			int(t.day),
+			int(t.hour),
+			int(t.minute),
+			int(t.second),
+			int(t.fraction),
+			c.opts.location())
+		return ExtTimestamp{Time: r, Picosecond: int(t.fraction2)}, nil
+	case C.SQL_C_TYPE_TIMESTAMP_EXT_TZ:
+		return timeFromTZ((*sql_TIMESTAMP_STRUCT_EXT_TZ)(p)), nil
 	case C.SQL_C_TYPE_DATE:
 		t := (*sql_DATE_STRUCT)(p)
+		if c.opts.CivilDateTime {
+			return Date{Year: int(t.year), Month: time.Month(t.month), Day: int(t.day)}, nil
+		}
 		r := time.Date(int(t.year),
 			time.Month(t.month),
 			int(t.day),
-			0, 0, 0, 0, time.Local)
+			0, 0, 0, 0, c.opts.location())
 		return r, nil
 	case C.SQL_C_TYPE_TIME:
 		t := (*sql_TIME_STRUCT)(p)
-		r := time.Date(0, 0, 0,
+		if c.opts.CivilDateTime {
+			return TimeOfDay{Hour: int(t.hour), Minute: int(t.minute), Second: int(t.second)}, nil
+		}
+		// January 1 of year 0, like time.Parse for a time without a date
+		r := time.Date(0, time.January, 1,
 			int(t.hour),
 			int(t.minute),
 			int(t.second),
 			0,
-			time.Local)
+			c.opts.location())
 		return r, nil
 	case C.SQL_C_BINARY:
 		return buf[:c.len], nil
@@ -245,6 +370,23 @@ func (c *column) value() (driver.Value, error) {
 	return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: unsupported column ctype %d", c.ctype)
 }
 
+// timeFromTZ converts a TIMESTAMP WITH TIME ZONE value to time.Time
+// in a fixed zone with the value's offset.
+func timeFromTZ(t *sql_TIMESTAMP_STRUCT_EXT_TZ) time.Time {
+	offset := int(t.timezone_hour)*3600 + int(t.timezone_minute)*60
+	if t.timezone_hour < 0 {
+		offset = int(t.timezone_hour)*3600 - int(t.timezone_minute)*60
+	}
+	return time.Date(int(t.year),
+		time.Month(t.month),
+		int(t.day),
+		int(t.hour),
+		int(t.minute),
+		int(t.second),
+		int(t.fraction),
+		time.FixedZone("", offset))
+}
+
 func describeColumn(h C.SQLHSTMT, idx int, namebuf []uint16) (namelen int,
 	sqltype C.SQLSMALLINT, size C.SQLULEN, ret C.SQLRETURN, nullOK bool, scale C.SQLSMALLINT) {
 	var l, nullable C.SQLSMALLINT
@@ -257,7 +399,81 @@ func describeColumn(h C.SQLHSTMT, idx int, namebuf []uint16) (namelen int,
 	return int(l), sqltype, size, ret, nullable == C.SQL_NULLABLE, scale
 }
 
-func newColumn(h C.SQLHSTMT, idx int) (*column, error) {
+// colAttrString returns a character attribute of column idx.
+func colAttrString(h C.SQLHSTMT, idx int, field C.SQLUSMALLINT) (string, C.SQLRETURN) {
+	buf := make([]uint16, 130)
+	var l C.SQLSMALLINT
+	for {
+		ret := C.SQLColAttributeW(h, C.SQLUSMALLINT(idx+1), field,
+			C.SQLPOINTER(unsafe.Pointer(&buf[0])), C.SQLSMALLINT(len(buf)*2), &l, nil)
+		if !success(ret) {
+			return "", ret
+		}
+		// l is the attribute length in bytes
+		n := int(l) / 2
+		if n < len(buf) {
+			return strings.TrimSpace(utf16ToString(buf[:n])), ret
+		}
+		buf = make([]uint16, n+1)
+	}
+}
+
+// columnInfo returns the ColumnInfo of c. The attributes are read from
+// SQLColAttribute on the first call, so a result set that nobody asks
+// about costs no SQLColAttribute calls. An attribute that the server
+// or the DB2 CLI version doesn't support is left empty.
+func (c *column) columnInfo() ColumnInfo {
+	if c.info != nil {
+		return *c.info
+	}
+	info := &ColumnInfo{}
+	var serverType string
+	for _, a := range []struct {
+		field C.SQLUSMALLINT
+		dest  *string
+	}{
+		{C.SQL_DESC_NAME, &info.Name},
+		{C.SQL_DESC_LABEL, &info.Label},
+		{C.SQL_DESC_TYPE_NAME, &serverType},
+		{C.SQL_DESC_DISTINCT_TYPE, &info.DistinctType},
+		{C.SQL_DESC_SCHEMA_NAME, &info.SchemaName},
+		{C.SQL_DESC_TABLE_NAME, &info.TableName},
+		{C.SQL_DESC_BASE_TABLE_NAME, &info.BaseTableName},
+		{C.SQL_DESC_BASE_COLUMN_NAME, &info.BaseColumnName},
+	} {
+		if v, ret := colAttrString(c.h, c.idx, a.field); success(ret) {
+			*a.dest = v
+		}
+	}
+	if v, ret := sqlColAttributeNum(c.h, c.idx+1, C.SQL_DESC_AUTO_UNIQUE_VALUE); success(ret) {
+		info.AutoIncrement = v == C.SQL_TRUE
+	}
+	if v, ret := sqlColAttributeNum(c.h, c.idx+1, C.SQL_DESC_UPDATABLE); success(ret) {
+		info.Updatable = v == C.SQL_ATTR_WRITE
+	}
+	if v, ret := sqlColAttributeNum(c.h, c.idx+1, C.SQL_DESC_DISPLAY_SIZE); success(ret) {
+		info.DisplaySize = v
+	}
+	if v, ret := sqlColAttributeNum(c.h, c.idx+1, C.SQL_DESC_OCTET_LENGTH); success(ret) {
+		info.OctetLength = v
+	}
+	if info.Name == "" {
+		info.Name = c.name
+	}
+	info.TypeName = sqlTypeName(c.sqltype)
+	if info.TypeName == "UNKNOWN" && serverType != "" {
+		info.TypeName = serverType
+	}
+	info.Nullable = c.nullable
+	info.Precision = c.size
+	info.Scale = c.scale
+	c.info = info
+	return *info
+}
+
+// describeNewColumn returns the description of column idx of a prepared
+// or executed statement.
+func describeNewColumn(h C.SQLHSTMT, idx int, opts *Connector) (*column, error) {
 	namebuf := make([]uint16, 150)
 	namelen, sqltype, size, ret, nullable, scale := describeColumn(h, idx, namebuf)
 	if ret == C.SQL_SUCCESS_WITH_INFO && namelen > len(namebuf) {
@@ -268,18 +484,28 @@ func newColumn(h C.SQLHSTMT, idx int) (*column, error) {
 		return nil, formatError(C.SQL_HANDLE_STMT, C.SQLHANDLE(h))
 	}
 	col := &column{
+		opts:     opts,
 		h:        h,
 		idx:      idx,
 		name:     utf16ToString(namebuf[:namelen]),
 		nullable: nullable,
 		size:     int64(size),
 		scale:    int64(scale),
+		sqltype:  sqltype,
 	}
+	return col, nil
+}
+
+func newColumn(h C.SQLHSTMT, idx int, opts *Connector) (*column, error) {
+	col, err := describeNewColumn(h, idx, opts)
+	if err != nil {
+		return nil, err
+	}
+	sqltype, size, scale := col.sqltype, col.size, col.scale
 
 	// [set column C-Type and allocate byte buffer to hold value from the database]
-	col.sqltype = sqltype
 	switch sqltype {
-	case C.SQL_BIT:
+	case C.SQL_BIT, C.SQL_BOOLEAN:
 		col.ctype = C.SQL_C_BIT
 		col.data = make([]byte, 1)
 	case C.SQL_TINYINT, C.SQL_SMALLINT, C.SQL_INTEGER:
@@ -292,9 +518,21 @@ func newColumn(h C.SQLHSTMT, idx int) (*column, error) {
 		col.ctype = C.SQL_C_DOUBLE
 		col.data = make([]byte, 8)
 	case C.SQL_TYPE_TIMESTAMP:
+		if scale > 9 {
+			// Fetching TIMESTAMP(10) to TIMESTAMP(12) into the
+			// nanosecond struct fails with fractional truncation.
+			var v sql_TIMESTAMP_STRUCT_EXT
+			col.ctype = C.SQL_C_TYPE_TIMESTAMP_EXT
+			col.data = make([]byte, int(unsafe.Sizeof(v)))
+			break
+		}
 		var v sql_TIMESTAMP_STRUCT
 		col.ctype = C.SQL_C_TYPE_TIMESTAMP
 		col.data = make([]byte, int(unsafe.Sizeof(v)))
+	case C.SQL_TYPE_TIMESTAMP_WITH_TIMEZONE:
+		var v sql_TIMESTAMP_STRUCT_EXT_TZ
+		col.ctype = C.SQL_C_TYPE_TIMESTAMP_EXT_TZ
+		col.data = make([]byte, int(unsafe.Sizeof(v)))
 	case C.SQL_TYPE_DATE:
 		var v sql_DATE_STRUCT
 		col.ctype = C.SQL_C_TYPE_DATE
@@ -308,15 +546,36 @@ func newColumn(h C.SQLHSTMT, idx int) (*column, error) {
 		// in DB2 IBM Knowledge Center, default C type for SQL_DECFLOAT is CHAR
 		// https://www.ibm.com/support/knowledgecenter/en/SSEPGG_11.1.0/com.ibm.db2.luw.apdv.cli.doc/doc/r0000526.html
 		l := int(size)
+		if opts.UTF8 && (sqltype == C.SQL_CHAR || sqltype == C.SQL_VARCHAR) {
+			// the size is in bytes of the database code page;
+			// a character can take up to 3 bytes in UTF-8
+			l *= 3
+		}
 		l += 1 // room for null-termination character
 		col.ctype = C.SQL_C_CHAR
 		col.data = make([]byte, l)
-	case C.SQL_WCHAR, C.SQL_WVARCHAR:
+	case C.SQL_WCHAR, C.SQL_WVARCHAR, C.SQL_GRAPHIC, C.SQL_VARGRAPHIC:
+		if opts.UTF8 {
+			// Fetch as UTF-8; a UTF-16 code unit takes up to 3 bytes.
+			col.ctype = C.SQL_C_CHAR
+			col.data = make([]byte, int(size)*3+1)
+			break
+		}
+		// GRAPHIC size is in double-byte characters.
+		// Fetch it as UTF-16 instead of the default SQL_C_DBCHAR,
+		// which is in the database graphic code page.
 		l := int(size)
 		l += 1 // for null-termination character
 		l *= 2 // wchars are 2 bytes each
 		col.ctype = C.SQL_C_WCHAR
 		col.data = make([]byte, l)
+	case C.SQL_LONGVARGRAPHIC, C.SQL_DBCLOB:
+		// Use SQLGetData like XML because the buffer for the
+		// declared size can be up to 2 GB.
+		col.ctype = C.SQL_C_WCHAR
+		if opts.UTF8 {
+			col.ctype = C.SQL_C_CHAR
+		}
 	case C.SQL_BINARY, C.SQL_VARBINARY, C.SQL_BLOB:
 		col.ctype = C.SQL_C_BINARY
 		col.data = make([]byte, size)
@@ -330,7 +589,7 @@ func newColumn(h C.SQLHSTMT, idx int) (*column, error) {
 	}
 	// only use SQLBindCol if we were able to allocate a byte buffer for the column
 	if len(col.data) > 0 {
-		ret = C.SQLBindCol(h, C.SQLUSMALLINT(idx+1),
+		ret := C.SQLBindCol(h, C.SQLUSMALLINT(idx+1),
 			col.ctype, C.SQLPOINTER(unsafe.Pointer(&col.data[0])),
 			C.SQLLEN(len(col.data)), &col.len)
 		if !success(ret) {
@@ -20,15 +20,45 @@ type conn struct {
 	closed bool
 	// if true then autocommit is off and driver.Tx will commit or rollback
 	tx bool
+	// driver options from the Connector or the connection string
+	opts Connector
+	// row count of the last statement; see RowsAffected
+	rowsAffected int64
+	// procedure parameters for Call by procedure name
+	procs map[string][]procParam
+	// server information, read by Info on first use
+	info *ServerInfo
 }
 
 func (d *impl) Open(dsn string) (driver.Conn, error) {
+	c, err := parseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return d.connect(c)
+}
+
+// connect opens a connection using the connection string and
+// the driver options in c.
+func (d *impl) connect(c *Connector) (driver.Conn, error) {
 	var hdbc C.SQLHANDLE
+	dsn := c.DSN
+	if p := c.timestampPrecision(); p < 0 || p > 12 {
+		return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: TimestampPrecision %d is not between 0 and 12", p)
+	}
 	re := regexp.MustCompile(`(?i:sqlconnect)\s*;`)
 
-	ret := C.SQLAllocHandle(C.SQL_HANDLE_DBC, d.henv, &hdbc)
+	henv := d.henv
+	if c.UTF8 {
+		var err error
+		henv, err = d.utf8Env()
+		if err != nil {
+			return nil, err
+		}
+	}
+	ret := C.SQLAllocHandle(C.SQL_HANDLE_DBC, henv, &hdbc)
 	if !success(ret) {
-		return nil, formatError(C.SQL_HANDLE_ENV, d.henv)
+		return nil, formatError(C.SQL_HANDLE_ENV, henv)
 	}
 	if re.MatchString(dsn) {
 		m := make(map[string]string)
@@ -70,7 +100,31 @@ func (d *impl) Open(dsn string) (driver.Conn, error) {
 		return nil, formatError(C.SQL_HANDLE_DBC, hdbc)
 	}
 
-	return &conn{hdbc: hdbc}, nil
+	cn := &conn{hdbc: hdbc, opts: *c}
+	if c.UTF8 {
+		if err := cn.checkUTF8(); err != nil {
+			cn.Close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+// checkUTF8 returns an error if the application code page of the connection
+// isn't 1208, which the UTF8 option needs. The code page of the UTF8
+// environment is set by utf8Env; this catches a DB2 CLI that ignores it.
+func (c *conn) checkUTF8() error {
+	var cp C.SQLUINTEGER
+	ret := C.SQLGetInfoW(C.SQLHDBC(c.hdbc), C.SQL_APPLICATION_CODEPAGE, C.SQLPOINTER(unsafe.Pointer(&cp)), 0, nil)
+	if !success(ret) {
+		return formatError(C.SQL_HANDLE_DBC, c.hdbc)
+	}
+	if cp != 1208 {
+		return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: "+
+			"UTF8 option needs the application code page 1208 but it is %d; "+
+			"set DB2CODEPAGE=1208 in the environment or with db2set", cp)
+	}
+	return nil
 }
 
 func (c *conn) Close() error {
@@ -103,12 +157,26 @@ func (c *conn) Prepare(sql string) (driver.Stmt, error) {
 // Similar to Prepare but additionally uses a context. If the context is cancelled then
 // the function returns an error and a nil statement handle.
 func (c *conn) PrepareContext(ctx context.Context, sql string) (driver.Stmt, error) {
+	s, err := c.prepare(ctx, sql, false)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// prepare prepares sql on a new statement handle. With autoIPD, DB2 CLI fills
+// in the implementation parameter descriptor, which holds the direction of
+// the parameters of a CALL, when it prepares the statement.
+func (c *conn) prepare(ctx context.Context, sql string, autoIPD bool) (*stmt, error) {
 	var hstmt C.SQLHANDLE = C.SQL_NULL_HSTMT // stmt handle
 
 	if c.closed {
 		return nil, errors.New("database/sql/driver: [asifjalil][CLI Driver]: called Prepare but the conn is closed")
 	}
 
+	// rewrite named parameter markers to ?
+	sql, markers := parseMarkers(sql)
+
 	// allocates a stmt handle to hstmt
 	wsql := stringToUTF16Ptr(sql)
 	// allocate stmt handle
@@ -121,15 +189,28 @@ func (c *conn) PrepareContext(ctx context.Context, sql string) (driver.Stmt, err
 	// The prepare request is not sent to the server until either
 	// SQLDescribeParam(), SQLExecute(), SQLNumResultCols(), SQLDescribeCol(), or
 	// SQLColAttribute() is called using the same statement handle as the prepared statement.
-	// Disable deferred prepare with the assumption that the user expects the statement
-	// to be prepared right now and get back any error.
-	ret = C.SQLSetStmtAttr(C.SQLHSTMT(hstmt),
-		C.SQL_ATTR_DEFERRED_PREPARE,
-		C.SQLPOINTER(uintptr(C.SQL_DEFERRED_PREPARE_OFF)), 0)
-	if !success(ret) {
-		err := formatError(C.SQL_HANDLE_STMT, hstmt)
-		C.SQLFreeHandle(C.SQL_HANDLE_STMT, hstmt)
-		return nil, err
+	// Unless the DeferredPrepare option is set, disable deferred prepare with the
+	// assumption that the user expects the statement to be prepared right now and
+	// get back any error.
+	if !c.opts.DeferredPrepare {
+		ret = C.SQLSetStmtAttr(C.SQLHSTMT(hstmt),
+			C.SQL_ATTR_DEFERRED_PREPARE,
+			C.SQLPOINTER(uintptr(C.SQL_DEFERRED_PREPARE_OFF)), 0)
+		if !success(ret) {
+			err := formatError(C.SQL_HANDLE_STMT, hstmt)
+			C.SQLFreeHandle(C.SQL_HANDLE_STMT, hstmt)
+			return nil, err
+		}
+	}
+	if autoIPD {
+		ret = C.SQLSetStmtAttr(C.SQLHSTMT(hstmt),
+			C.SQL_ATTR_ENABLE_AUTO_IPD,
+			C.SQLPOINTER(uintptr(C.SQL_TRUE)), 0)
+		if !success(ret) {
+			err := formatError(C.SQL_HANDLE_STMT, hstmt)
+			C.SQLFreeHandle(C.SQL_HANDLE_STMT, hstmt)
+			return nil, err
+		}
 	}
 
 	ret = C.SQLPrepareW(C.SQLHSTMT(hstmt),
@@ -156,9 +237,10 @@ func (c *conn) PrepareContext(ctx context.Context, sql string) (driver.Stmt, err
 		return nil, ctx.Err()
 	}
 	return &stmt{
-		conn:  c,
-		hstmt: hstmt,
-		sql:   sql}, nil
+		conn:    c,
+		hstmt:   hstmt,
+		sql:     sql,
+		markers: markers}, nil
 }
 
 func (c *conn) Begin() (driver.Tx, error) {
@@ -270,3 +352,25 @@ func (c *conn) endTx(commit bool) error {
 
 	return nil
 }
+
+// RowsAffected returns the number of rows that the last statement executed on
+// dbConn inserted, updated, merged or deleted, or -1 for other statements.
+// Use it to get the row count of a statement run with Query, which returns
+// no result set for a statement that isn't a query:
+//
+//	rows, err := conn.QueryContext(ctx, "DELETE FROM staff WHERE dept = 20")
+//	...
+//	rows.Close()
+//	n, err := cli.RowsAffected(conn)
+func RowsAffected(dbConn *sql.Conn) (int64, error) {
+	var n int64
+	err := dbConn.Raw(func(dc interface{}) error {
+		c, ok := dc.(*conn)
+		if !ok {
+			return errors.New("database/sql/driver: [asifjalil][CLI Driver]: RowsAffected needs a cli connection")
+		}
+		n = c.rowsAffected
+		return nil
+	})
+	return n, err
+}
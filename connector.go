@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Connector implements driver.Connector. Use it with sql.OpenDB to set
+// driver options without encoding them in the connection string:
+//
+//	db := sql.OpenDB(&cli.Connector{
+//		DSN:             "DATABASE=sample; UID=me; PWD=secret;",
+//		DeferredPrepare: true,
+//	})
+//
+// The same options can be set in the connection string given to sql.Open.
+// The driver removes them from the string before passing it to DB2 CLI.
+type Connector struct {
+	// DSN is the connection string. See the package documentation
+	// for the syntax.
+	DSN string
+
+	// DeferredPrepare leaves DB2 CLI deferred prepare on, so a statement is
+	// sent to the server with its first execution instead of in a separate
+	// round trip at Prepare time. Errors in the SQL text are then returned
+	// by Exec or Query instead of Prepare.
+	// Connection string keyword: DeferredPrepare=1.
+	DeferredPrepare bool
+
+	// TimestampPrecision is the number of fractional second digits, 0 to 12,
+	// used to bind a time.Time when the parameter is not a TIMESTAMP column
+	// whose precision can be described. It is used only if
+	// TimestampPrecisionSet is true; otherwise 9 is used, which keeps the
+	// nanoseconds of a time.Time.
+	// Connection string keyword: TimestampPrecision=n, which sets both fields.
+	TimestampPrecision    int
+	TimestampPrecisionSet bool
+
+	// Location is the time zone of DATE, TIME and TIMESTAMP values in the
+	// database. A time.Time parameter is converted to Location before it
+	// is bound, and values read from the database are returned in Location.
+	// If nil, time.Local is used.
+	// Connection string keyword: Location=name, where name is
+	// a time zone name accepted by time.LoadLocation, such as UTC.
+	Location *time.Location
+
+	// CivilDateTime returns DATE values as Date and TIME values as TimeOfDay
+	// instead of time.Time.
+	// Connection string keyword: CivilDateTime=1.
+	CivilDateTime bool
+
+	// TrimCharPadding removes the trailing blanks that pad fixed-length
+	// CHAR and GRAPHIC values. VARCHAR, VARGRAPHIC and CLOB values
+	// are returned as stored.
+	// Connection string keyword: TrimCharPadding=1.
+	TrimCharPadding bool
+
+	// UTF8 binds string parameters as UTF-8 SQL_C_CHAR data and fetches
+	// character columns as UTF-8, without the conversion to and from UTF-16
+	// that the driver does otherwise. UTF8 connections use a separate DB2 CLI
+	// environment with the client code page 1208 (UTF-8); Connect fails if
+	// DB2 CLI doesn't use that code page.
+	// Connection string keyword: UTF8=1.
+	UTF8 bool
+
+	// LastInsertID makes Result.LastInsertId of a single-row INSERT ... VALUES
+	// return IDENTITY_VAL_LOCAL(), which costs a round trip after the INSERT.
+	// LastInsertId of another INSERT, such as a multi-row INSERT or
+	// INSERT ... SELECT, returns an error; use InsertReturning for its keys.
+	// Connection string keyword: LastInsertID=1.
+	LastInsertID bool
+}
+
+// Connect returns a new connection to the database.
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	select {
+	default:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return drv.connect(c)
+}
+
+// Driver returns the "cli" driver.
+func (c *Connector) Driver() driver.Driver {
+	return &drv
+}
+
+// OpenConnector implements driver.DriverContext.
+func (d *impl) OpenConnector(dsn string) (driver.Connector, error) {
+	return parseDSN(dsn)
+}
+
+// parseDSN removes driver options from dsn and returns them together with
+// the remaining connection string as a Connector.
+func parseDSN(dsn string) (*Connector, error) {
+	c := &Connector{}
+	var keep []string
+	for _, pair := range strings.Split(dsn, ";") {
+		z := strings.SplitN(pair, "=", 2)
+		key := strings.ToUpper(strings.TrimSpace(z[0]))
+		if len(z) != 2 || !isDriverOption(key) {
+			keep = append(keep, pair)
+			continue
+		}
+		if err := c.setOption(key, strings.TrimSpace(z[1])); err != nil {
+			return nil, err
+		}
+	}
+	c.DSN = strings.Join(keep, ";")
+	return c, nil
+}
+
+func isDriverOption(key string) bool {
+	switch key {
+	case "DEFERREDPREPARE", "TIMESTAMPPRECISION", "LOCATION", "CIVILDATETIME",
+		"TRIMCHARPADDING", "UTF8", "LASTINSERTID":
+		return true
+	}
+	return false
+}
+
+func (c *Connector) setOption(key, value string) error {
+	var err error
+	switch key {
+	case "DEFERREDPREPARE":
+		c.DeferredPrepare, err = parseBoolOption(value)
+	case "TIMESTAMPPRECISION":
+		var n int
+		n, err = strconv.Atoi(value)
+		if err == nil && (n < 0 || n > 12) {
+			err = strconv.ErrRange
+		}
+		c.TimestampPrecision, c.TimestampPrecisionSet = n, true
+	case "LOCATION":
+		c.Location, err = time.LoadLocation(value)
+	case "CIVILDATETIME":
+		c.CivilDateTime, err = parseBoolOption(value)
+	case "TRIMCHARPADDING":
+		c.TrimCharPadding, err = parseBoolOption(value)
+	case "UTF8":
+		c.UTF8, err = parseBoolOption(value)
+	case "LASTINSERTID":
+		c.LastInsertID, err = parseBoolOption(value)
+	}
+	if err != nil {
+		return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: invalid value %q for connection string keyword %s", value, key)
+	}
+	return nil
+}
+
+// parseBoolOption accepts the values strconv.ParseBool accepts
+// plus YES and NO.
+func parseBoolOption(value string) (bool, error) {
+	switch strings.ToUpper(value) {
+	case "YES":
+		return true, nil
+	case "NO":
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// timestampPrecision returns TimestampPrecision or its default.
+func (c *Connector) timestampPrecision() int {
+	if !c.TimestampPrecisionSet {
+		return 9
+	}
+	return c.TimestampPrecision
+}
+
+// location returns Location or its default.
+func (c *Connector) location() *time.Location {
+	if c.Location == nil {
+		return time.Local
+	}
+	return c.Location
+}
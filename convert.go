@@ -18,6 +18,15 @@ import (
 
 var errNilPtr = errors.New("destination pointer is nil") // embedded in descriptive error
 
+// convertAssignIn is convertAssign for values of a connection whose
+// DATE, TIME and TIMESTAMP values are in loc.
+func convertAssignIn(dest, src interface{}, loc *time.Location) error {
+	if ts, ok := dest.(*ExtTimestamp); ok && ts != nil {
+		return ts.scanIn(src, loc)
+	}
+	return convertAssign(dest, src)
+}
+
 // convertAssign copies to dest the value in src, converting it if possible.
 // An error is returned if the copy would result in loss of information.
 // dest should be a pointer type.
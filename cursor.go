@@ -0,0 +1,112 @@
+package cli
+
+/*
+#include <sqlcli1.h>
+*/
+import "C"
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Cursor is a result set returned by a stored procedure in an OUT CURSOR
+// parameter. Use a *Cursor as the Dest of sql.Out:
+//
+//	var cur cli.Cursor
+//	_, err := conn.ExecContext(ctx, "CALL get_staff(?, ?)", 20, sql.Out{Dest: &cur})
+//	...
+//	defer cur.Close()
+//	for cur.Next() {
+//		err = cur.Scan(&id, &name)
+//		...
+//	}
+//	err = cur.Err()
+//
+// The cursor is read on the connection that ran the CALL, so run the CALL
+// with a sql.Conn or sql.Tx and close the cursor before the connection
+// is used for anything else.
+type Cursor struct {
+	s      *stmt
+	rows   *rows
+	values []driver.Value
+	err    error
+}
+
+// newCursor returns a Cursor that reads the result set of statement handle h.
+func newCursor(c *conn, h C.SQLHANDLE) (*Cursor, error) {
+	s := &stmt{conn: c, hstmt: h, sql: "CURSOR", resultSets: 1}
+	err := s.bindColumns()
+	if err != nil {
+		C.SQLFreeHandle(C.SQL_HANDLE_STMT, h)
+		return nil, err
+	}
+	s.rows = true
+	return &Cursor{s: s, rows: &rows{s: s}}, nil
+}
+
+// Columns returns the column names of the cursor.
+func (cur *Cursor) Columns() ([]string, error) {
+	if cur.rows == nil {
+		return nil, errors.New("database/sql/driver: [asifjalil][CLI Driver]: Cursor is closed")
+	}
+	return cur.rows.Columns(), nil
+}
+
+// Next prepares the next row for Scan. It returns false after the last row
+// or an error, which Err returns.
+func (cur *Cursor) Next() bool {
+	if cur.rows == nil || cur.err != nil {
+		return false
+	}
+	if cur.values == nil {
+		cur.values = make([]driver.Value, len(cur.s.cols))
+	}
+	err := cur.rows.Next(cur.values)
+	if err == io.EOF {
+		return false
+	}
+	if err != nil {
+		cur.err = err
+		return false
+	}
+	return true
+}
+
+// Scan copies the columns of the current row into the values pointed at by dest,
+// like sql.Rows.Scan.
+func (cur *Cursor) Scan(dest ...interface{}) error {
+	if cur.rows == nil {
+		return errors.New("database/sql/driver: [asifjalil][CLI Driver]: Scan on closed Cursor")
+	}
+	if len(dest) != len(cur.values) {
+		return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: expected %d destination arguments in Scan, not %d",
+			len(cur.values), len(dest))
+	}
+	for i, v := range cur.values {
+		if err := convertAssignIn(dest[i], v, cur.s.conn.opts.location()); err != nil {
+			return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: Scan error on column index %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// Err returns the error, if any, that ended Next.
+func (cur *Cursor) Err() error {
+	return cur.err
+}
+
+// Close closes the cursor and frees its statement handle.
+// Close is idempotent.
+func (cur *Cursor) Close() error {
+	if cur.rows == nil {
+		return nil
+	}
+	err := cur.rows.Close()
+	cur.rows = nil
+	if cerr := cur.s.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,130 @@
+package cli
+
+/*
+#include <sqlcli1.h>
+*/
+import "C"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"unsafe"
+)
+
+// ParamDirection is the direction of a parameter marker.
+type ParamDirection int
+
+// Parameter directions. A parameter of a statement other than CALL is
+// an input parameter. ParamReturnValue and ParamResultColumn are only
+// used by Procedures and Functions.
+const (
+	ParamInput        ParamDirection = C.SQL_PARAM_INPUT
+	ParamInputOutput  ParamDirection = C.SQL_PARAM_INPUT_OUTPUT
+	ParamOutput       ParamDirection = C.SQL_PARAM_OUTPUT
+	ParamReturnValue  ParamDirection = C.SQL_RETURN_VALUE
+	ParamResultColumn ParamDirection = C.SQL_RESULT_COL
+)
+
+// ParamInfo describes a parameter marker with the values DB2 CLI
+// returns from SQLDescribeParam.
+type ParamInfo struct {
+	TypeName  string // DB2 data type name, for example VARCHAR
+	Size      int64  // precision of a numeric parameter; length of other parameters
+	Scale     int64  // scale of a numeric parameter
+	Nullable  bool   // true if the parameter accepts null
+	Direction ParamDirection
+}
+
+// Description is the description of a prepared statement.
+type Description struct {
+	Columns []ColumnInfo // result set columns; empty if the statement returns no rows
+	Params  []ParamInfo  // parameter markers in order
+}
+
+// Describe prepares query on dbConn and returns the description of its result
+// columns and parameter markers. The statement is not executed.
+//
+//	conn, err := db.Conn(ctx)
+//	...
+//	d, err := cli.Describe(ctx, conn, "SELECT name, salary FROM staff WHERE dept = ?")
+func Describe(ctx context.Context, dbConn *sql.Conn, query string) (*Description, error) {
+	var d *Description
+	err := dbConn.Raw(func(dc interface{}) error {
+		c, ok := dc.(*conn)
+		if !ok {
+			return errors.New("database/sql/driver: [asifjalil][CLI Driver]: Describe needs a cli connection")
+		}
+		s, err := c.prepare(ctx, query, true)
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+		d, err = s.describe()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// describe returns the description of the prepared statement.
+func (s *stmt) describe() (*Description, error) {
+	h := C.SQLHSTMT(s.hstmt)
+	d := &Description{}
+
+	var ncols C.SQLSMALLINT
+	ret := C.SQLNumResultCols(h, &ncols)
+	if !success(ret) {
+		return nil, formatError(C.SQL_HANDLE_STMT, s.hstmt)
+	}
+	for i := 0; i < int(ncols); i++ {
+		col, err := describeNewColumn(h, i, &s.conn.opts)
+		if err != nil {
+			return nil, err
+		}
+		d.Columns = append(d.Columns, col.columnInfo())
+	}
+
+	var nparams C.SQLSMALLINT
+	ret = C.SQLNumParams(h, &nparams)
+	if !success(ret) {
+		return nil, formatError(C.SQL_HANDLE_STMT, s.hstmt)
+	}
+	for i := 0; i < int(nparams); i++ {
+		var sqltype, decimalDigits, nullable C.SQLSMALLINT
+		var size C.SQLULEN
+		ret = C.SQLDescribeParam(h, C.SQLUSMALLINT(i+1),
+			&sqltype, &size, &decimalDigits, &nullable)
+		if !success(ret) {
+			return nil, formatError(C.SQL_HANDLE_STMT, s.hstmt)
+		}
+		d.Params = append(d.Params, ParamInfo{
+			TypeName:  sqlTypeName(sqltype),
+			Size:      int64(size),
+			Scale:     int64(decimalDigits),
+			Nullable:  nullable == C.SQL_NULLABLE,
+			Direction: s.paramDirection(i),
+		})
+	}
+	return d, nil
+}
+
+// paramDirection returns the direction of the parameter at idx from the
+// implementation parameter descriptor, which DB2 CLI fills in for the
+// parameters of a CALL statement prepared with SQL_ATTR_ENABLE_AUTO_IPD.
+func (s *stmt) paramDirection(idx int) ParamDirection {
+	var ipd C.SQLHDESC
+	ret := C.SQLGetStmtAttr(C.SQLHSTMT(s.hstmt), C.SQL_ATTR_IMP_PARAM_DESC,
+		C.SQLPOINTER(unsafe.Pointer(&ipd)), 0, nil)
+	if !success(ret) {
+		return ParamInput
+	}
+	var v C.SQLSMALLINT
+	ret = C.SQLGetDescField(ipd, C.SQLSMALLINT(idx+1), C.SQL_DESC_PARAMETER_TYPE,
+		C.SQLPOINTER(unsafe.Pointer(&v)), 0, nil)
+	if !success(ret) || v == C.SQL_PARAM_TYPE_UNKNOWN {
+		return ParamInput
+	}
+	return ParamDirection(v)
+}
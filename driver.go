@@ -72,6 +72,95 @@
 //
 // Search **SQLDriverConnect** in DB2 LUW *Information Center* for more detail.
 //
+// ### Driver Options
+// The connection string can also hold options for this driver. The driver removes
+// them from the string before calling SQLConnect or SQLDriverConnect.
+// Keywords are case insensitive:
+//
+//      DeferredPrepare=1       send the PREPARE with the first execute; errors are returned by Exec or Query
+//      TimestampPrecision=n    fractional second digits for a time.Time parameter that isn't a
+//                              TIMESTAMP column; the default is 9
+//      Location=name           time zone of DATE, TIME and TIMESTAMP values, such as UTC;
+//                              the default is the local time zone
+//      CivilDateTime=1         return DATE as Date and TIME as TimeOfDay instead of time.Time
+//      TrimCharPadding=1       remove the trailing blanks of CHAR and GRAPHIC values
+//      UTF8=1                  bind and fetch strings as UTF-8 with the client code page 1208
+//      LastInsertID=1          return IDENTITY_VAL_LOCAL() from Result.LastInsertId of a single-row
+//                              INSERT ... VALUES
+//
+// The options are also fields of **Connector**, which can be used with sql.OpenDB:
+//	db := sql.OpenDB(&cli.Connector{DSN: "DATABASE=sample;", DeferredPrepare: true})
+//
+// ### Parameter Types
+// A Go value is bound to a parameter marker with a DB2 type based on its Go type.
+// Wrap the value in **Date**, **TimeOfDay**, **Time**, **Timestamp**, **CLOB**, **BLOB**, **XML**,
+// **Graphic**, **Char**, or **Decimal** to bind it with that DB2 type instead:
+//	db.Exec("INSERT INTO t(c1, c2) VALUES(?, ?)", cli.CLOB(text), cli.Decimal("12.50"))
+//
+// A string or []byte parameter for a FOR BIT DATA or BLOB column is bound as
+// binary data without a code page conversion. **UUID** is a [16]byte stored
+// as CHAR(16) FOR BIT DATA.
+//
+// A time.Time for a TIMESTAMP WITH TIME ZONE parameter is bound with its time
+// zone offset. A TIMESTAMP parameter has no time zone, so a time.Time for it, or
+// for a parameter DB2 can't describe, is converted to the Location driver
+// option and its offset is not stored.
+//
+// ### Named Parameters
+// The named parameter markers :name and @name are rewritten to ? and bound to
+// sql.Named arguments with the same name, ignoring case. A name can be used
+// more than once. In a CALL with named procedure arguments, a ? after
+// "name =>" is bound to the sql.Named argument of that name:
+//	db.Exec("UPDATE staff SET salary = :pay WHERE id = :id", sql.Named("id", 10), sql.Named("pay", 900.50))
+//	db.Exec("CALL proc(p_out => ?, p_in => ?)", sql.Named("p_in", 1), sql.Named("p_out", sql.Out{Dest: &v}))
+//
+// A name starts with a letter or _ and can have letters, digits, _, $, # and @.
+// A : or @ inside an identifier such as A@B, in a string literal, a delimited
+// identifier or a comment, or anywhere in a CREATE or ALTER statement is kept.
+//
+// ### Stored Procedures
+// Pass OUT and INOUT parameters as sql.Out. DB2 assigns the OUT parameters of
+// a CALL that returns result sets after the last result set is read or the
+// rows are closed. In a transaction, an OUT CLOB, BLOB or DBCLOB parameter is
+// read through a LOB locator, so its buffer isn't sized from the declared
+// length. With autocommit the CALL commits, which frees its locators, so the
+// output of an OUT LOB parameter is limited to 1 MB like the output of an OUT
+// XML or INOUT LOB parameter, which is limited to 1 MB or the input size.
+// A longer output is an error.
+// An OUT CURSOR parameter is read with a **Cursor**:
+//	var cur cli.Cursor
+//	_, err := conn.ExecContext(ctx, "CALL get_staff(?)", sql.Out{Dest: &cur})
+//
+// A Go slice other than []byte wrapped in **Array** is bound to an ARRAY parameter,
+// and an OUT ARRAY is assigned to a pointer to a slice. The element type comes
+// from the ARRAY type:
+//	var names []string
+//	_, err := db.Exec("CALL staff_names(?, ?)", cli.Array{Slice: []int{10, 20}}, sql.Out{Dest: &names})
+//
+// The elements of a DECIMAL or DECFLOAT ARRAY are bound and returned as strings,
+// so an OUT element keeps its digits. DB2 CLI has no way to bind a ROW type, so
+// a procedure with a ROW parameter needs a wrapper procedure that takes its fields.
+//
+// **Call** looks up the parameters of a procedure in the catalog, binds the
+// arguments by name, and returns the OUT values and the result sets:
+//	res, err := cli.Call(ctx, conn, "hr.raise_salary", map[string]interface{}{"id": 10, "pct": 5})
+//
+// ### Catalog
+// **Tables**, **Columns**, **PrimaryKeys**, **ForeignKeys**, **Indexes**,
+// **SpecialColumns** and **TablePrivileges** call the DB2 CLI catalog
+// functions on a sql.Conn and return the results as Go structs:
+//	cols, err := cli.Columns(ctx, conn, "HR", "EMPLOYEE", "%")
+//
+// **Procedures** and **Functions** return the routines with their parameters,
+// for example to generate Go wrappers for SQL PL routines.
+//
+// **Info** returns the server and driver information of a sql.Conn, such as
+// the DB2 version, the database code page and the supported data types:
+//	info, err := cli.Info(ctx, conn)
+//	if err == nil && info.AtLeast(11, 1) {
+//		...
+//	}
+//
 // ## Installation
 // IBM DB2 for Linux, Unix and Windows (DB2 LUW) implements its own ODBC driver.
 // This package uses the DB2 ODBC/CLI driver through cgo.
@@ -109,12 +198,25 @@ SQLRETURN sqlSetEnvUIntPtrAttr(SQLHENV environmentHandle, SQLINTEGER attribute,
 SQLRETURN sqlSetConnectUIntPtrAttr(SQLHDBC connectionHandle, SQLINTEGER attribute, uintptr_t valuePtr, SQLINTEGER stringLength) {
     return SQLSetConnectAttr(connectionHandle, attribute, (SQLPOINTER)valuePtr, stringLength);
 }
+// The numeric attribute argument is SQLPOINTER or SQLLEN* depending on the DB2 version.
+SQLRETURN sqlColAttributeNum(SQLHSTMT statementHandle, SQLUSMALLINT columnNumber, SQLUSMALLINT fieldIdentifier, SQLLEN *numericAttribute) {
+    return SQLColAttributeW(statementHandle, columnNumber, fieldIdentifier, NULL, 0, NULL, (void *)numericAttribute);
+}
+// SQL_ATTR_CLIENT_CODEPAGE is missing from old DB2 headers.
+SQLRETURN sqlSetEnvCodepage(SQLHENV environmentHandle, SQLUINTEGER codepage) {
+#ifdef SQL_ATTR_CLIENT_CODEPAGE
+    return SQLSetEnvAttr(environmentHandle, SQL_ATTR_CLIENT_CODEPAGE, (SQLPOINTER)(uintptr_t)codepage, 0);
+#else
+    return SQL_ERROR;
+#endif
+}
 */
 import "C"
 
 import (
 	"database/sql"
 	"fmt"
+	"sync"
 )
 
 var drv impl
@@ -136,6 +238,35 @@ type (
 		fraction C.SQLUINTEGER
 	}
 
+	// sql_TIMESTAMP_STRUCT_EXT is used for TIMESTAMP(10) to TIMESTAMP(12).
+	// fraction holds nanoseconds and fraction2 the picoseconds after them.
+	sql_TIMESTAMP_STRUCT_EXT struct {
+		year      C.SQLSMALLINT
+		month     C.SQLUSMALLINT
+		day       C.SQLUSMALLINT
+		hour      C.SQLUSMALLINT
+		minute    C.SQLUSMALLINT
+		second    C.SQLUSMALLINT
+		fraction  C.SQLUINTEGER
+		fraction2 C.SQLUINTEGER
+	}
+
+	// sql_TIMESTAMP_STRUCT_EXT_TZ is used for TIMESTAMP WITH TIME ZONE.
+	// The time zone is timezone_hour hours and timezone_minute minutes
+	// from UTC; the sign of the offset is the sign of timezone_hour.
+	sql_TIMESTAMP_STRUCT_EXT_TZ struct {
+		year            C.SQLSMALLINT
+		month           C.SQLUSMALLINT
+		day             C.SQLUSMALLINT
+		hour            C.SQLUSMALLINT
+		minute          C.SQLUSMALLINT
+		second          C.SQLUSMALLINT
+		fraction        C.SQLUINTEGER
+		fraction2       C.SQLUINTEGER
+		timezone_hour   C.SQLSMALLINT
+		timezone_minute C.SQLSMALLINT
+	}
+
 	sql_TIME_STRUCT struct {
 		hour   C.SQLUSMALLINT
 		minute C.SQLUSMALLINT
@@ -145,26 +276,64 @@ type (
 
 type impl struct {
 	henv C.SQLHANDLE // environment handle
+
+	// environment handle of UTF8 connections, allocated
+	// by utf8Env on first use
+	utf8Once sync.Once
+	utf8Henv C.SQLHANDLE
+	utf8Err  error
 }
 
 func initDriver() error {
+	var err error
+	drv.henv, err = allocEnv()
+	return err
+}
+
+// allocEnv allocates an environment handle for ODBC v3.
+func allocEnv() (C.SQLHANDLE, error) {
+	var henv C.SQLHANDLE
 	// Allocate environment handle
-	ret := C.SQLAllocHandle(C.SQL_HANDLE_ENV, C.SQL_NULL_HANDLE, &drv.henv)
+	ret := C.SQLAllocHandle(C.SQL_HANDLE_ENV, C.SQL_NULL_HANDLE, &henv)
 	if !success(ret) {
-		return fmt.Errorf("database/sql/driver: [asifjalil][CLI driver]Failed to allocate environment handle; rc: %d ", int(ret))
+		return henv, fmt.Errorf("database/sql/driver: [asifjalil][CLI driver]Failed to allocate environment handle; rc: %d ", int(ret))
 	}
 
 	//use ODBC v3
-	ret = sqlSetEnvUIntPtrAttr(C.SQLHENV(drv.henv),
+	ret = sqlSetEnvUIntPtrAttr(C.SQLHENV(henv),
 		C.SQL_ATTR_ODBC_VERSION,
 		uintptr(C.SQL_OV_ODBC3), 0)
 
 	if !success(ret) {
-		defer C.SQLFreeHandle(C.SQL_HANDLE_ENV, drv.henv)
-		return formatError(C.SQL_HANDLE_ENV, drv.henv)
+		defer C.SQLFreeHandle(C.SQL_HANDLE_ENV, henv)
+		return henv, formatError(C.SQL_HANDLE_ENV, henv)
 	}
 
-	return nil
+	return henv, nil
+}
+
+// utf8Env returns the environment handle of connections with the UTF8
+// option. DB2 CLI fixes the client code page of an environment before its
+// first connection, so UTF8 connections get their own environment with
+// the code page 1208 (UTF-8).
+func (d *impl) utf8Env() (C.SQLHANDLE, error) {
+	d.utf8Once.Do(func() {
+		henv, err := allocEnv()
+		if err != nil {
+			d.utf8Err = err
+			return
+		}
+		ret := C.sqlSetEnvCodepage(C.SQLHENV(henv), 1208)
+		if !success(ret) {
+			C.SQLFreeHandle(C.SQL_HANDLE_ENV, henv)
+			d.utf8Err = fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: " +
+				"UTF8 option failed to set the client code page 1208; " +
+				"this DB2 CLI version needs DB2CODEPAGE=1208 in the environment or with db2set")
+			return
+		}
+		d.utf8Henv = henv
+	})
+	return d.utf8Henv, d.utf8Err
 }
 
 func init() {
@@ -184,3 +353,9 @@ func sqlSetConnectUIntPtrAttr(connectionHandle C.SQLHDBC, attribute C.SQLINTEGER
 	r := C.sqlSetConnectUIntPtrAttr(C.SQLHDBC(connectionHandle), C.SQLINTEGER(attribute), C.uintptr_t(valuePtr), C.SQLINTEGER(stringLength))
 	return C.SQLRETURN(r)
 }
+
+func sqlColAttributeNum(statementHandle C.SQLHSTMT, columnNumber int, fieldIdentifier C.SQLUSMALLINT) (int64, C.SQLRETURN) {
+	var n C.SQLLEN
+	r := C.sqlColAttributeNum(statementHandle, C.SQLUSMALLINT(columnNumber), fieldIdentifier, &n)
+	return int64(n), C.SQLRETURN(r)
+}
@@ -1,6 +1,7 @@
 package cli_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"database/sql/driver"
@@ -14,7 +15,7 @@ import (
 	"testing"
 	"time"
 
-	_ "github.com/asifjalil/cli"
+	"github.com/asifjalil/cli"
 )
 
 type testDB struct {
@@ -35,6 +36,12 @@ func getDB2Error(sqlerr error) (int, string, bool) {
 }
 
 func newTestDB() (*testDB, error) {
+	return newTestDBWithOptions("")
+}
+
+// newTestDBWithOptions appends driver options, such as "DeferredPrepare=1;",
+// to the test connection string.
+func newTestDBWithOptions(options string) (*testDB, error) {
 	config := struct {
 		database string
 		uid      string
@@ -64,7 +71,7 @@ func newTestDB() (*testDB, error) {
 		connStr = os.Getenv("DATABASE_DSN")
 	}
 
-	db, err := sql.Open("cli", connStr)
+	db, err := sql.Open("cli", connStr+options)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +115,7 @@ func TestTimeStamp(t *testing.T) {
 	// But Go timestamp accuracy is up to a nanosecond or 9 digits.
 	// So the last 3 digits in 9 digits must be 0.
 	ts := time.Date(2009, time.November, 10, 23, 6, 29, 10011001000, time.UTC)
-	db, err := newTestDB()
+	db, err := newTestDBWithOptions(" Location=UTC;")
 	if err != nil {
 		die(t, "failed to create db object: %v", err)
 	}
@@ -144,20 +151,10 @@ func TestTimeStamp(t *testing.T) {
 	case err != nil:
 		die(t, "insert into IN_TRAY failed because %v", err)
 	default:
-		// Timestamps are stored as is but without the timezone information.
-		// When a timestamp is returned from the database, the driver/Go assumes
-		// local timezone.
-		info(t, "database timestamp with local timezone: %v", db_ts)
-		// In this case we used UTC, so change the local timezone to UTC.
-		db_ts = time.Date(db_ts.Year(),
-			db_ts.Month(),
-			db_ts.Day(),
-			db_ts.Hour(),
-			db_ts.Minute(),
-			db_ts.Second(),
-			db_ts.Nanosecond(),
-			time.UTC)
-		if !ts.Equal(db_ts) {
+		// Timestamps are stored without the timezone information.
+		// The Location option makes the driver bind and return them in UTC.
+		info(t, "database timestamp: %v", db_ts)
+		if !ts.Equal(db_ts) || db_ts.Location() != time.UTC {
 			die(t, "Expected: %v| Got: %v", ts, db_ts)
 		}
 	}
@@ -169,6 +166,183 @@ func TestTimeStamp(t *testing.T) {
 	}
 }
 
+func TestLocation(t *testing.T) {
+	// 2009-11-10 23:06:29 in UTC is 2009-11-11 08:06:29 in Tokyo.
+	ts := time.Date(2009, time.November, 10, 23, 6, 29, 0, time.UTC)
+	db, err := newTestDBWithOptions(" Location=Asia/Tokyo;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	var got time.Time
+	var s string
+	err = db.QueryRow("VALUES(CAST(? AS TIMESTAMP(0)), CHAR(CAST(? AS TIMESTAMP(0))))", ts, ts).Scan(&got, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(ts) || got.Location().String() != "Asia/Tokyo" {
+		t.Errorf("wanted %v in Asia/Tokyo, got %v", ts, got)
+	}
+	if s != "2009-11-11-08.06.29" {
+		t.Errorf("wanted the database value 2009-11-11-08.06.29, got %s", s)
+	}
+	// a TIMESTAMP(12) is returned as an ExtTimestamp in the same Location
+	var ext cli.ExtTimestamp
+	err = db.QueryRow("VALUES(CAST(? AS TIMESTAMP(12)))", ts).Scan(&ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ext.Time.Equal(ts) || ext.Time.Location().String() != "Asia/Tokyo" {
+		t.Errorf("wanted %v in Asia/Tokyo, got %v", ts, ext.Time)
+	}
+}
+
+func TestTimeStampTZ(t *testing.T) {
+	ts := time.Date(2009, time.November, 10, 23, 6, 29, 123456000, time.FixedZone("", -(3*3600 + 30*60)))
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	// DB2 for z/OS has TIMESTAMP WITH TIME ZONE; DB2 LUW doesn't.
+	_, err = db.Exec("CREATE TABLE TS_TZ(TS TIMESTAMP(6) WITH TIME ZONE)")
+	if err != nil {
+		t.Skipf("TIMESTAMP WITH TIME ZONE is not supported: %v", err)
+	}
+	defer db.Exec("DROP TABLE TS_TZ")
+
+	_, err = db.Exec("INSERT INTO TS_TZ VALUES(?)", ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT TS FROM TS_TZ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	ct, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := ct[0].DatabaseTypeName(); name != "TIMESTAMP WITH TIME ZONE" {
+		t.Errorf("wanted TIMESTAMP WITH TIME ZONE, got %s", name)
+	}
+	var got time.Time
+	for rows.Next() {
+		if err := rows.Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, gotOffset := got.Zone()
+	if !got.Equal(ts) || gotOffset != -(3*3600+30*60) {
+		t.Errorf("wanted %v, got %v", ts, got)
+	}
+}
+
+func TestCivilDateTime(t *testing.T) {
+	db, err := newTestDBWithOptions(" CivilDateTime=1;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	wantDate := cli.Date{Year: 2009, Month: time.November, Day: 10}
+	wantTime := cli.TimeOfDay{Hour: 23, Minute: 6, Second: 29}
+	rows, err := db.Query("VALUES(?, ?, CHAR(?, ISO), CHAR(?, ISO))", wantDate, wantTime, wantDate, wantTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	ct, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := ct[0].ScanType(); st != reflect.TypeOf(cli.Date{}) {
+		t.Errorf("DATE: wanted scan type cli.Date, got %v", st)
+	}
+	if st := ct[1].ScanType(); st != reflect.TypeOf(cli.TimeOfDay{}) {
+		t.Errorf("TIME: wanted scan type cli.TimeOfDay, got %v", st)
+	}
+
+	var gotDate cli.Date
+	var gotTime cli.TimeOfDay
+	var d, tm string
+	for rows.Next() {
+		if err := rows.Scan(&gotDate, &gotTime, &d, &tm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gotDate != wantDate || d != "2009-11-10" {
+		t.Errorf("DATE: wanted %v, got %v (%s)", wantDate, gotDate, d)
+	}
+	if gotTime != wantTime || tm != "23.06.29" {
+		t.Errorf("TIME: wanted %v, got %v (%s)", wantTime, gotTime, tm)
+	}
+}
+
+func TestTimeStampPrecision(t *testing.T) {
+	ts := time.Date(2009, time.November, 10, 23, 6, 29, 123456789, time.Local)
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	_, err = db.Exec("CREATE TABLE TS_PRECISION(TS3 TIMESTAMP(3), TS9 TIMESTAMP(9), TS12 TIMESTAMP(12))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE TS_PRECISION")
+
+	ext := cli.ExtTimestamp{Time: ts, Picosecond: 12}
+	_, err = db.Exec("INSERT INTO TS_PRECISION VALUES(?, ?, ?)", ts, ts, ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ts3, ts9 time.Time
+	var gotExt cli.ExtTimestamp
+	err = db.QueryRow("SELECT TS3, TS9, TS12 FROM TS_PRECISION").Scan(&ts3, &ts9, &gotExt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := ts.Truncate(time.Millisecond); !ts3.Equal(want) {
+		t.Errorf("TIMESTAMP(3): wanted %v, got %v", want, ts3)
+	}
+	if !ts9.Equal(ts) {
+		t.Errorf("TIMESTAMP(9): wanted %v, got %v", ts, ts9)
+	}
+	if gotExt.Time.Location() != time.Local {
+		t.Errorf("TIMESTAMP(12): wanted the local time zone, got %v", gotExt.Time.Location())
+	}
+	if !gotExt.Time.Equal(ext.Time) || gotExt.Picosecond != ext.Picosecond {
+		t.Errorf("TIMESTAMP(12): wanted %v, got %v", ext, gotExt)
+	}
+}
+
+func TestTimeStampPrecisionZero(t *testing.T) {
+	ts := time.Date(2009, time.November, 10, 23, 6, 29, 123456789, time.Local)
+	db, err := newTestDBWithOptions("TimestampPrecision=0;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	// a VARCHAR parameter gets the precision of the driver option
+	var s string
+	err = db.QueryRow("VALUES CAST(? AS VARCHAR(40))", ts).Scan(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2009-11-10-23.06.29"; s != want {
+		t.Errorf("wanted %q without fractional seconds, got %q", want, s)
+	}
+}
+
 func TestXML(t *testing.T) {
 	testCases := []struct {
 		qry string
@@ -371,6 +545,255 @@ func TestRowsColumnTypes(t *testing.T) {
 	}
 }
 
+func TestColumnInfo(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	_, err = db.Exec("CREATE DISTINCT TYPE COLINFO_MONEY AS DECIMAL(9,2) WITH COMPARISONS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP DISTINCT TYPE COLINFO_MONEY")
+	_, err = db.Exec(`CREATE TABLE COLINFO(ID INT NOT NULL GENERATED ALWAYS AS IDENTITY,
+		NAME VARGRAPHIC(10), PRICE COLINFO_MONEY, NOTE LONG VARCHAR)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE COLINFO")
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var infos []cli.ColumnInfo
+	var typeNames []string
+	err = conn.Raw(func(dc interface{}) error {
+		st, err := dc.(driver.ConnPrepareContext).PrepareContext(ctx,
+			"SELECT ID, NAME AS N, PRICE, NOTE FROM COLINFO")
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+		rows, err := st.(driver.StmtQueryContext).QueryContext(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		r := rows.(interface{ ColumnInfo(int) cli.ColumnInfo })
+		for i := range rows.Columns() {
+			infos = append(infos, r.ColumnInfo(i))
+			typeNames = append(typeNames, rows.(driver.RowsColumnTypeDatabaseTypeName).ColumnTypeDatabaseTypeName(i))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !infos[0].AutoIncrement || infos[1].AutoIncrement {
+		t.Errorf("wanted only ID to be auto-increment, got %+v", infos)
+	}
+	if infos[1].Label != "N" || infos[1].BaseColumnName != "NAME" {
+		t.Errorf("wanted label N of column NAME, got %+v", infos[1])
+	}
+	if infos[1].TypeName != "VARGRAPHIC" || infos[1].OctetLength != 20 {
+		t.Errorf("wanted VARGRAPHIC of 20 bytes, got %+v", infos[1])
+	}
+	if !strings.HasSuffix(infos[2].DistinctType, "COLINFO_MONEY") || infos[2].TypeName != "DECIMAL" {
+		t.Errorf("wanted DECIMAL distinct type COLINFO_MONEY, got %+v", infos[2])
+	}
+	// the driver has no name for LONG VARCHAR; DB2 reports it
+	if infos[3].TypeName == "" || infos[3].TypeName == "UNKNOWN" {
+		t.Errorf("wanted the type name of LONG VARCHAR, got %+v", infos[3])
+	}
+	for i, info := range infos {
+		if typeNames[i] != info.TypeName {
+			t.Errorf("column %d: wanted type name %s, got %s", i, info.TypeName, typeNames[i])
+		}
+	}
+	for _, info := range infos {
+		if info.TableName != "COLINFO" {
+			t.Errorf("wanted table COLINFO, got %+v", info)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	d, err := cli.Describe(ctx, conn, "SELECT NAME, SALARY FROM STAFF WHERE DEPT = ? AND JOB = ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCols := []struct {
+		name     string
+		typeName string
+		size     int64
+		scale    int64
+	}{
+		{"NAME", "VARCHAR", 9, 0},
+		{"SALARY", "DECIMAL", 7, 2},
+	}
+	if len(d.Columns) != len(wantCols) {
+		t.Fatalf("wanted %d columns, got %+v", len(wantCols), d.Columns)
+	}
+	for i, want := range wantCols {
+		got := d.Columns[i]
+		if got.Name != want.name || got.TypeName != want.typeName ||
+			got.Precision != want.size || got.Scale != want.scale {
+			t.Errorf("column %d: wanted %+v, got %+v", i, want, got)
+		}
+	}
+	wantParams := []cli.ParamInfo{
+		{TypeName: "SMALLINT", Size: 5, Nullable: true, Direction: cli.ParamInput},
+		{TypeName: "CHARACTER", Size: 5, Nullable: true, Direction: cli.ParamInput},
+	}
+	if !reflect.DeepEqual(wantParams, d.Params) {
+		t.Errorf("wanted %+v, got %+v", wantParams, d.Params)
+	}
+
+	// Describe must not execute the statement
+	_, err = db.Exec("CREATE TABLE DESCRIBE(ID INT)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE DESCRIBE")
+	d, err = cli.Describe(ctx, conn, "INSERT INTO DESCRIBE VALUES(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Columns) != 0 || len(d.Params) != 0 {
+		t.Errorf("wanted no columns or parameters, got %+v", d)
+	}
+	var n int
+	if err = db.QueryRow("SELECT COUNT(*) FROM DESCRIBE").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("wanted an empty table, got %d rows", n)
+	}
+
+	_, err = db.Exec(`CREATE OR REPLACE PROCEDURE describe_dirs(IN p_in INT, INOUT p_inout INT, OUT p_out INT)
+	LANGUAGE SQL
+	BEGIN
+		SET p_out = p_in + p_inout;
+	END`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP PROCEDURE describe_dirs")
+	d, err = cli.Describe(ctx, conn, "CALL describe_dirs(?, ?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDirs := []cli.ParamDirection{cli.ParamInput, cli.ParamInputOutput, cli.ParamOutput}
+	if len(d.Params) != len(wantDirs) {
+		t.Fatalf("wanted %d parameters, got %+v", len(wantDirs), d.Params)
+	}
+	for i, want := range wantDirs {
+		if d.Params[i].Direction != want {
+			t.Errorf("parameter %d: wanted direction %d, got %+v", i, want, d.Params[i])
+		}
+	}
+
+	_, err = cli.Describe(ctx, conn, "SELECT * FROM NO_SUCH_TABLE")
+	if err == nil {
+		t.Error("wanted an error for a missing table")
+	}
+}
+
+func TestLastInsertId(t *testing.T) {
+	db, err := newTestDBWithOptions(" LastInsertID=1;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	_, err = db.Exec("CREATE TABLE IDENT(ID INT NOT NULL GENERATED ALWAYS AS IDENTITY (START WITH 10), NAME VARCHAR(10))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE IDENT")
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	for want := int64(10); want < 12; want++ {
+		res, err := conn.ExecContext(ctx, "INSERT INTO IDENT(NAME) VALUES(?)", "one")
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != want {
+			t.Errorf("wanted id %d, got %d", want, id)
+		}
+	}
+
+	res, err := conn.ExecContext(ctx, "UPDATE IDENT SET NAME = 'two'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = res.LastInsertId(); err == nil {
+		t.Error("wanted an error from LastInsertId of an UPDATE")
+	}
+
+	rows, err := cli.InsertReturning(ctx, conn, "INSERT INTO IDENT(NAME) VALUES(?), (?)",
+		[]string{"ID", "NAME"}, "three", "four")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var id int64
+		var name string
+		if err = rows.Scan(&id, &name); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, fmt.Sprintf("%d:%s", id, name))
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"12:three", "13:four"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("wanted %v, got %v", want, got)
+	}
+	rows.Close()
+
+	res, err = conn.ExecContext(ctx, "INSERT INTO IDENT(NAME) SELECT NAME FROM IDENT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = res.LastInsertId(); err == nil || !strings.Contains(err.Error(), "InsertReturning") {
+		t.Errorf("wanted an error pointing to InsertReturning from a multi-row INSERT, got %v", err)
+	}
+}
+
 func TestQueryTimeout(t *testing.T) {
 	var rc int
 	db, err := newTestDB()
@@ -471,37 +894,93 @@ func TestTxPrepare(t *testing.T) {
 	tx.Commit()
 }
 
-func TestTxContext(t *testing.T) {
-	db, err := newTestDB()
+func TestDeferredPrepare(t *testing.T) {
+	db, err := newTestDBWithOptions(" DeferredPrepare=1;")
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer db.close()
 
-	opts := sql.TxOptions{
-		Isolation: sql.LevelDefault,
-		ReadOnly:  true,
+	// With deferred prepare the error is returned by Query instead of Prepare.
+	stmt, err := db.Prepare("select 11 from abcd")
+	if err != nil {
+		die(t, "Expected Prepare to succeed with deferred prepare; got %v", err)
 	}
-	tx, err := db.BeginTx(context.Background(), &opts)
+	_, err = stmt.Query()
+	if _, sqlstate, ok := getDB2Error(err); !ok || sqlstate != "42704" {
+		die(t, "Expected Query to fail with SQLSTATE=42704; got %v", err)
+	}
+	stmt.Close()
+
+	// NumInput and SQLDescribeParam for a nil parameter work before the
+	// statement reaches the server.
+	var val sql.NullInt64
+	err = db.QueryRow("values(cast(? as integer))", nil).Scan(&val)
 	if err != nil {
-		t.Fatal(err)
+		die(t, "nil parameter failed: %v", err)
+	}
+	if val.Valid {
+		die(t, "Expected NULL; got %d", val.Int64)
 	}
 
-	stmt, err := tx.PrepareContext(context.Background(), "select count(*) from syscat.tables")
+	_, err = db.Exec("values(cast(? as integer))")
+	if err == nil {
+		die(t, "Expected Exec to fail because of a missing argument")
+	}
+	info(t, "%s", err)
+
+	// The prepare warning 01504 of a DELETE without WHERE comes from Exec,
+	// which returns SQL_SUCCESS_WITH_INFO. Any other warning fails Exec.
+	// The table needs a row: a DELETE of no rows returns SQL_NO_DATA_FOUND.
+	_, err = db.Exec("CREATE TABLE deferred_prepare(c1 INT)")
 	if err != nil {
 		t.Fatal(err)
 	}
-
-	rows, err := stmt.Query()
+	defer db.Exec("DROP TABLE deferred_prepare")
+	_, err = db.Exec("INSERT INTO deferred_prepare VALUES(1)")
 	if err != nil {
 		t.Fatal(err)
 	}
-	rows.Close()
-	stmt.Close()
-	tx.Commit()
+	res, err := db.Exec("DELETE FROM deferred_prepare")
+	if err != nil {
+		die(t, "Expected DELETE without WHERE to succeed with warning 01504; got %v", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		die(t, "Expected DELETE to delete 1 row; got %d, %v", n, err)
+	}
 }
 
-func TestDouble(t *testing.T) {
+func TestTxContext(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	opts := sql.TxOptions{
+		Isolation: sql.LevelDefault,
+		ReadOnly:  true,
+	}
+	tx, err := db.BeginTx(context.Background(), &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, err := tx.PrepareContext(context.Background(), "select count(*) from syscat.tables")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := stmt.Query()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+	stmt.Close()
+	tx.Commit()
+}
+
+func TestDouble(t *testing.T) {
 	db, err := newTestDB()
 	if err != nil {
 		t.Fatal(err)
@@ -717,6 +1196,296 @@ func TestBigInt(t *testing.T) {
 	}
 }
 
+func TestNativeNumbers(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	i32 := int32(-2147483648)
+	var nilI16 *int16
+	tests := []struct {
+		name string
+		qry  string
+		arg  interface{}
+		want string
+	}{
+		{name: "int8", qry: "VALUES (CHAR(CAST (? AS SMALLINT)))", arg: int8(-128), want: "-128"},
+		{name: "uint8", qry: "VALUES (CHAR(CAST (? AS SMALLINT)))", arg: uint8(255), want: "255"},
+		{name: "int16", qry: "VALUES (CHAR(CAST (? AS SMALLINT)))", arg: int16(-32768), want: "-32768"},
+		{name: "uint16", qry: "VALUES (CHAR(CAST (? AS INTEGER)))", arg: uint16(65535), want: "65535"},
+		{name: "*int32", qry: "VALUES (CHAR(CAST (? AS INTEGER)))", arg: &i32, want: "-2147483648"},
+		{name: "nil *int16", qry: "VALUES (COALESCE(CHAR(CAST (? AS SMALLINT)), 'NULL'))", arg: nilI16, want: "NULL"},
+		{name: "uint32", qry: "VALUES (CHAR(CAST (? AS BIGINT)))", arg: uint32(4294967295), want: "4294967295"},
+		{name: "uint64", qry: "VALUES (CHAR(CAST (? AS DECIMAL(20, 0))))", arg: uint64(math.MaxUint64), want: "18446744073709551615"},
+		{name: "float32", qry: "VALUES (CHAR(CAST (? AS REAL)))", arg: float32(1.5), want: "+1.5000000E+000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			err := db.QueryRow(tt.qry, tt.arg).Scan(&got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(got) != tt.want {
+				t.Errorf("wanted %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTypedParams(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	_, err = db.Exec(`CREATE TABLE TYPED_PARAMS(D DATE, T TIME, TS TIMESTAMP(6),
+		C CLOB(1K), B BLOB(1K), X XML, DEC DECIMAL(7, 2), CH CHAR(3))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE TYPED_PARAMS")
+
+	ts := time.Date(2009, time.November, 10, 23, 6, 29, 123456789, time.UTC)
+	_, err = db.Exec("INSERT INTO TYPED_PARAMS VALUES(?, ?, ?, ?, ?, ?, ?, ?)",
+		cli.DateOf(ts), cli.Time(ts), cli.Timestamp{Time: ts, Precision: 6},
+		cli.CLOB("clob"), cli.BLOB([]byte{0, 1, 2}), cli.XML("<a>xml</a>"),
+		cli.Decimal("-12345.67"), cli.Char("abc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var d, tm, tstamp, c, x, dec, ch string
+	var b []byte
+	err = db.QueryRow(`SELECT CHAR(D, ISO), CHAR(T, ISO), CHAR(TS), C, B,
+		XMLSERIALIZE(X AS VARCHAR(100)), CHAR(DEC), CH FROM TYPED_PARAMS`).Scan(&d, &tm, &tstamp, &c, &b, &x, &dec, &ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{d, tm, tstamp, c, hex.EncodeToString(b), x, dec, ch}
+	want := []string{"2009-11-10", "23.06.29", "2009-11-10-23.06.29.123456", "clob", "000102", "<a>xml</a>", "-12345.67", "abc"}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("wanted %q, got %q", want, got)
+	}
+}
+
+func TestGraphic(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	_, err = db.Exec("CREATE TABLE GRAPHICS(G GRAPHIC(5), VG VARGRAPHIC(20), DC DBCLOB(1M))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE GRAPHICS")
+
+	// DBCLOB larger than the 1024 byte SQLGetData buffer
+	want := []string{"ＡＢ", "日本語テキスト", strings.Repeat("データ", 1000)}
+	_, err = db.Exec("INSERT INTO GRAPHICS VALUES(?, ?, ?)", cli.Graphic(want[0]), want[1], cli.Graphic(want[2]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT G, VG, DC FROM GRAPHICS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	ct, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range []string{"GRAPHIC", "VARGRAPHIC", "DBCLOB"} {
+		if got := ct[i].DatabaseTypeName(); got != name {
+			t.Errorf("wanted %s, got %s", name, got)
+		}
+	}
+	got := make([]string, 3)
+	for rows.Next() {
+		if err := rows.Scan(&got[0], &got[1], &got[2]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// GRAPHIC(5) is padded with double-byte blanks
+	got[0] = strings.TrimRight(got[0], "　 ")
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("wanted %q, got %q", want, got)
+	}
+}
+
+func TestBoolean(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	// BOOLEAN columns are available from DB2 11.1.1.1
+	_, err = db.Exec("CREATE TABLE BOOLEANS(B BOOLEAN, S SMALLINT, C CHAR(1))")
+	if err != nil {
+		t.Skipf("BOOLEAN is not supported: %v", err)
+	}
+	defer db.Exec("DROP TABLE BOOLEANS")
+
+	_, err = db.Exec("INSERT INTO BOOLEANS VALUES(?, ?, ?)", true, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT B, S, C FROM BOOLEANS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	ct, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := ct[0].DatabaseTypeName(); name != "BOOLEAN" {
+		t.Errorf("wanted BOOLEAN, got %s", name)
+	}
+	if st := ct[0].ScanType(); st != reflect.TypeOf(false) {
+		t.Errorf("wanted scan type bool, got %v", st)
+	}
+	var b bool
+	var i int
+	var c string
+	for rows.Next() {
+		if err := rows.Scan(&b, &i, &c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !b || i != 1 || c != "0" {
+		t.Errorf("wanted true, 1, \"0\"; got %v, %d, %q", b, i, c)
+	}
+}
+
+func TestCharPadding(t *testing.T) {
+	qry := "VALUES(CAST('ab' AS CHAR(5)), CAST('ab ' AS VARCHAR(5)), CAST('ab' AS CHAR(5) FOR BIT DATA))"
+	tests := []struct {
+		options string
+		want    []interface{}
+	}{
+		{options: "", want: []interface{}{"ab   ", "ab ", []byte("ab   ")}},
+		{options: " TrimCharPadding=1;", want: []interface{}{"ab", "ab ", []byte("ab   ")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.options, func(t *testing.T) {
+			db, err := newTestDBWithOptions(tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.close()
+
+			got := make([]interface{}, 3)
+			err = db.QueryRow(qry).Scan(&got[0], &got[1], &got[2])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tt.want, got) {
+				t.Errorf("wanted %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestForBitData(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	_, err = db.Exec("CREATE TABLE BITDATA(ID CHAR(16) FOR BIT DATA, B VARCHAR(16) FOR BIT DATA)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP TABLE BITDATA")
+
+	id, err := cli.ParseUUID("00e8f5a2-8c1b-4c3e-9f00-d1b2c3ff0080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// bytes that a code page conversion would change
+	want := string([]byte{0x00, 0x80, 0xff, 0xc3, 0x28, 0x0a})
+	_, err = db.Exec("INSERT INTO BITDATA VALUES(?, ?)", id, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotID cli.UUID
+	var got string
+	err = db.QueryRow("SELECT ID, B FROM BITDATA WHERE ID = ?", id).Scan(&gotID, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotID != id {
+		t.Errorf("wanted %v, got %v", id, gotID)
+	}
+	if got != want {
+		t.Errorf("wanted %x, got %x", want, got)
+	}
+}
+
+func TestSupplementaryPlane(t *testing.T) {
+	want := "a\U0001F600\U0002070Eb"
+	for _, options := range []string{"", " UTF8=1;"} {
+		t.Run(options, func(t *testing.T) {
+			db, err := newTestDBWithOptions(options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.close()
+
+			var varchar, vargraphic string
+			err = db.QueryRow("VALUES(CAST(? AS VARCHAR(40)), CAST(? AS VARGRAPHIC(20)))",
+				want, want).Scan(&varchar, &vargraphic)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if varchar != want {
+				t.Errorf("VARCHAR: wanted %+q, got %+q", want, varchar)
+			}
+			if vargraphic != want {
+				t.Errorf("VARGRAPHIC: wanted %+q, got %+q", want, vargraphic)
+			}
+		})
+	}
+}
+
+func benchmarkStrings(b *testing.B, options string) {
+	db, err := newTestDBWithOptions(options)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.close()
+
+	stmt, err := db.Prepare("VALUES(CAST(? AS VARCHAR(200)))")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer stmt.Close()
+
+	in := strings.Repeat("Hello, 世界! ", 10)
+	var out string
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := stmt.QueryRow(in).Scan(&out); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStringsUTF16(b *testing.B) { benchmarkStrings(b, "") }
+
+func BenchmarkStringsUTF8(b *testing.B) { benchmarkStrings(b, " UTF8=1;") }
+
 func TestString(t *testing.T) {
 	password := "Pac1f1c"
 	db, err := newTestDB()
@@ -1143,76 +1912,839 @@ func TestSPStringInOut(t *testing.T) {
 	}
 }
 
-func TestSPClobOut(t *testing.T) {
+func TestSPResultSets(t *testing.T) {
 	db, err := newTestDB()
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer db.close()
 
-	_, err = db.Exec(`
-	 CREATE OR REPLACE PROCEDURE out_param_clob(IN empno char(6)
-		, IN resume_format varchar(10)
-		, OUT resume clob)
+	_, err = db.Exec(`CREATE OR REPLACE PROCEDURE test_resultsets(OUT p_count INT)
 	LANGUAGE SQL
-	SPECIFIC out_param_clob
+	DYNAMIC RESULT SETS 2
 	BEGIN
-		select resume into resume from emp_resume
-		where empno = empno and resume_format = resume_format
-		fetch first 1 row only;
-	END
-	 `)
+		DECLARE c1 CURSOR WITH RETURN FOR VALUES(1), (2);
+		DECLARE c2 CURSOR WITH RETURN FOR VALUES('a', 'b');
+		SET p_count = 2;
+		OPEN c1;
+		OPEN c2;
+	END`)
 	if err != nil {
 		t.Fatal(err)
 	}
-	defer func() {
-		db.Exec("DROP PROCEDURE out_param_clob")
-	}()
+	defer db.Exec("DROP PROCEDURE test_resultsets")
 
-	var resume string
-	procStmt := "CALL out_param_clob('000140', 'ascii', ?)"
-	_, err = db.ExecContext(context.Background(), procStmt,
-		sql.Out{Dest: &resume})
+	var count int
+	rows, err := db.Query("CALL test_resultsets(?)", sql.Out{Dest: &count})
 	if err != nil {
 		t.Fatal(err)
 	}
-	t.Logf("%s returned %s\n", procStmt, resume)
-}
-
-// To check if we can use sql.Query to run a non-select statement
-func TestDDLQuery(t *testing.T) {
-	tabname := "test"
-	createStmt := fmt.Sprintf("create table %s(col1 smallint)", tabname)
-	insertStmt := fmt.Sprintf("insert into %s values(1)", tabname)
-	selectStmt := fmt.Sprintf("select col1 from %s", tabname)
-	dropStmt := fmt.Sprintf("drop table %s", tabname)
+	var got [][]string
+	for {
+		var set []string
+		cols, err := rows.Columns()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dest := make([]interface{}, len(cols))
+		vals := make([]sql.RawBytes, len(cols))
+		for i := range dest {
+			dest[i] = &vals[i]
+		}
+		for rows.Next() {
+			if err := rows.Scan(dest...); err != nil {
+				t.Fatal(err)
+			}
+			for _, v := range vals {
+				set = append(set, string(v))
+			}
+		}
+		got = append(got, set)
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err = rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"1", "2"}, {"a", "b"}}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("wanted result sets %v, got %v", want, got)
+	}
+	if count != 2 {
+		t.Errorf("wanted OUT parameter 2, got %d", count)
+	}
 
-	type rowsAffected interface {
-		RowsAffected() int64
+	// Exec skips the result sets and still assigns the OUT parameter
+	count = 0
+	res, err := db.Exec("CALL test_resultsets(?)", sql.Out{Dest: &count})
+	if err != nil {
+		t.Fatal(err)
 	}
+	if n, _ := res.RowsAffected(); n != -1 {
+		t.Errorf("wanted -1 rows affected, got %d", n)
+	}
+	if count != 2 {
+		t.Errorf("wanted OUT parameter 2 from Exec, got %d", count)
+	}
+}
 
+func TestSPCursorOut(t *testing.T) {
 	db, err := newTestDB()
 	if err != nil {
 		t.Fatal(err)
 	}
 	defer db.close()
 
-	_, err = db.Query(createStmt)
-	if affected, ok := err.(rowsAffected); ok {
-		info(t, "%q: row affected %d", createStmt, affected.RowsAffected())
-	} else {
-		die(t, "%q failed: %v\n", createStmt, err)
+	_, err = db.Exec("CREATE OR REPLACE TYPE test_cursor AS CURSOR")
+	if err != nil {
+		t.Fatal(err)
 	}
-
-	_, err = db.Exec(insertStmt)
+	defer db.Exec("DROP TYPE test_cursor")
+	_, err = db.Exec(`CREATE OR REPLACE PROCEDURE test_cursorout(IN p_dept SMALLINT, OUT p_cur test_cursor)
+	LANGUAGE SQL
+	BEGIN
+		SET p_cur = CURSOR FOR SELECT id, name FROM staff WHERE dept = p_dept ORDER BY id;
+		OPEN p_cur;
+	END`)
 	if err != nil {
-		die(t, "%q failed: %v\n", insertStmt, err)
+		t.Fatal(err)
 	}
+	defer db.Exec("DROP PROCEDURE test_cursorout")
 
-	var val int
-	err = db.QueryRow(selectStmt).Scan(&val)
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
 	if err != nil {
-		die(t, "%q failed: %v\n", selectStmt, err)
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var cur cli.Cursor
+	_, err = conn.ExecContext(ctx, "CALL test_cursorout(?, ?)", 20, sql.Out{Dest: &cur})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cur.Close()
+
+	cols, err := cur.Columns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ID", "NAME"}; !reflect.DeepEqual(want, cols) {
+		t.Errorf("wanted columns %v, got %v", want, cols)
+	}
+	var got []string
+	for cur.Next() {
+		var id int
+		var name string
+		if err := cur.Scan(&id, &name); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, fmt.Sprintf("%d:%s", id, name))
+	}
+	if err = cur.Err(); err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	rows, err := conn.QueryContext(ctx, "SELECT id, name FROM staff WHERE dept = 20 ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, fmt.Sprintf("%d:%s", id, name))
+	}
+	rows.Close()
+	if len(want) == 0 || !reflect.DeepEqual(want, got) {
+		t.Errorf("wanted %v, got %v", want, got)
+	}
+	if err = cur.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSPArray(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	for _, ddl := range []string{
+		"CREATE OR REPLACE TYPE test_intarray AS INTEGER ARRAY[10]",
+		"CREATE OR REPLACE TYPE test_strarray AS VARCHAR(20) ARRAY[10]",
+		"CREATE OR REPLACE TYPE test_decarray AS DECIMAL(31, 2) ARRAY[10]",
+		`CREATE OR REPLACE PROCEDURE test_array(IN p_ids test_intarray,
+			OUT p_names test_strarray, INOUT p_doubled test_intarray, INOUT p_prices test_decarray)
+		LANGUAGE SQL
+		BEGIN
+			DECLARE i INT DEFAULT 1;
+			WHILE i <= CARDINALITY(p_ids) DO
+				SET p_names[i] = 'id ' || VARCHAR(p_ids[i]);
+				SET i = i + 1;
+			END WHILE;
+			SET i = 1;
+			WHILE i <= CARDINALITY(p_doubled) DO
+				SET p_doubled[i] = p_doubled[i] * 2;
+				SET i = i + 1;
+			END WHILE;
+			SET i = 1;
+			WHILE i <= CARDINALITY(p_prices) DO
+				SET p_prices[i] = p_prices[i] + 0.01;
+				SET i = i + 1;
+			END WHILE;
+		END`,
+	} {
+		if _, err = db.Exec(ddl); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() {
+		db.Exec("DROP PROCEDURE test_array")
+		db.Exec("DROP TYPE test_decarray")
+		db.Exec("DROP TYPE test_strarray")
+		db.Exec("DROP TYPE test_intarray")
+	}()
+
+	var names []string
+	doubled := []int64{1, 2, 3}
+	// DECIMAL elements keep digits that a float64 would round
+	prices := []string{"12345678901234567890.12", "-12.10"}
+	_, err = db.Exec("CALL test_array(?, ?, ?, ?)",
+		cli.Array{Slice: []int{7, 8}}, sql.Out{Dest: &names}, sql.Out{Dest: &doubled, In: true},
+		sql.Out{Dest: &prices, In: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"id 7", "id 8"}; !reflect.DeepEqual(want, names) {
+		t.Errorf("wanted OUT array %q, got %q", want, names)
+	}
+	if want := []int64{2, 4, 6}; !reflect.DeepEqual(want, doubled) {
+		t.Errorf("wanted INOUT array %v, got %v", want, doubled)
+	}
+	if want := []string{"12345678901234567890.13", "-12.09"}; !reflect.DeepEqual(want, prices) {
+		t.Errorf("wanted INOUT DECIMAL array %q, got %q", want, prices)
+	}
+
+	// a slice without cli.Array isn't an ARRAY
+	_, err = db.Exec("CALL test_array(?, ?, ?, ?)",
+		[]int{7, 8}, sql.Out{Dest: &names}, sql.Out{Dest: &doubled, In: true}, sql.Out{Dest: &prices, In: true})
+	if err == nil || !strings.Contains(err.Error(), "cli.Array") {
+		t.Errorf("wanted an error for a slice without cli.Array, got %v", err)
+	}
+	_, err = db.Exec("CALL test_array(?, ?, ?, ?)",
+		cli.Array{Slice: []uint64{math.MaxUint64}}, sql.Out{Dest: &names}, sql.Out{Dest: &doubled, In: true},
+		sql.Out{Dest: &prices, In: true})
+	if err == nil {
+		t.Error("wanted an error for a uint64 that overflows BIGINT")
+	}
+}
+
+func TestNamedParams(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	var a, b int
+	var s string
+	err = db.QueryRow("VALUES(CAST(:x AS INT), CAST(@y AS INT) + CAST(:x AS INT), ':z @w')",
+		sql.Named("y", 2), sql.Named("X", 1)).Scan(&a, &b, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != 1 || b != 3 || s != ":z @w" {
+		t.Errorf("wanted 1, 3, %q, got %d, %d, %q", ":z @w", a, b, s)
+	}
+
+	// missing and extra names
+	err = db.QueryRow("VALUES(CAST(:x AS INT))", sql.Named("y", 1)).Scan(&a)
+	if err == nil || !strings.Contains(err.Error(), `"x"`) {
+		t.Errorf("wanted a missing argument error, got %v", err)
+	}
+	err = db.QueryRow("VALUES(CAST(:x AS INT))", sql.Named("x", 1), sql.Named("y", 1)).Scan(&a)
+	if err == nil || !strings.Contains(err.Error(), `"y"`) {
+		t.Errorf("wanted an extra argument error, got %v", err)
+	}
+	// named arguments don't bind to ? by position
+	err = db.QueryRow("VALUES(CAST(? AS INT))", sql.Named("x", 1)).Scan(&a)
+	if err == nil {
+		t.Error("wanted an error for a named argument and a ? marker")
+	}
+
+	_, err = db.Exec(`CREATE OR REPLACE PROCEDURE test_named(IN p_a INT, IN p_b INT, OUT p_diff INT)
+	LANGUAGE SQL
+	BEGIN
+		SET p_diff = p_a - p_b;
+	END`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP PROCEDURE test_named")
+
+	var diff int
+	_, err = db.Exec("CALL test_named(p_b => ?, p_diff => ?, p_a => ?)",
+		sql.Named("p_diff", sql.Out{Dest: &diff}), sql.Named("p_a", 10), sql.Named("p_b", 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != 7 {
+		t.Errorf("wanted 7, got %d", diff)
+	}
+}
+
+func TestCall(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	_, err = db.Exec(`CREATE OR REPLACE PROCEDURE test_call(IN p_id INT, INOUT p_count INT, OUT p_name VARCHAR(20))
+	LANGUAGE SQL
+	DYNAMIC RESULT SETS 1
+	BEGIN
+		DECLARE c1 CURSOR WITH RETURN FOR VALUES(p_id, 'x');
+		SET p_count = p_count + 1;
+		SET p_name = 'name ' || VARCHAR(p_id);
+		OPEN c1;
+	END`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP PROCEDURE test_call")
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	res, err := cli.Call(ctx, conn, "test_call", map[string]interface{}{"p_id": 5, "P_COUNT": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.Out["P_COUNT"]; got != 2 {
+		t.Errorf("wanted INOUT P_COUNT 2, got %#v", got)
+	}
+	if got := res.Out["P_NAME"]; got != "name 5" {
+		t.Errorf("wanted OUT P_NAME %q, got %#v", "name 5", got)
+	}
+	if n, err := res.Int64("p_count"); err != nil || n != 2 {
+		t.Errorf("wanted Int64 2, got %d, %v", n, err)
+	}
+	if s, err := res.String("p_name"); err != nil || s != "name 5" {
+		t.Errorf("wanted String %q, got %q, %v", "name 5", s, err)
+	}
+	if _, err := res.Time("p_name"); err == nil {
+		t.Error("wanted an error for a VARCHAR as time.Time")
+	}
+	if _, err := res.Int64("p_nope"); err == nil {
+		t.Error("wanted an error for an unknown OUT parameter")
+	}
+	wantSets := []cli.ResultSet{{Columns: []string{"1", "2"}, Rows: [][]interface{}{{int32(5), "x"}}}}
+	if !reflect.DeepEqual(wantSets, res.ResultSets) {
+		t.Errorf("wanted result sets %#v, got %#v", wantSets, res.ResultSets)
+	}
+
+	// a struct with the signature from the cache
+	args := struct {
+		ID    int `cli:"p_id"`
+		Count int `cli:"p_count"`
+	}{ID: 6, Count: 10}
+	res, err = cli.Call(ctx, conn, "test_call", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.Out["P_COUNT"]; got != 11 {
+		t.Errorf("wanted INOUT P_COUNT 11, got %#v", got)
+	}
+
+	_, err = cli.Call(ctx, conn, "test_call", map[string]interface{}{"p_nope": 1})
+	if err == nil {
+		t.Error("wanted an error for an unknown parameter")
+	}
+	_, err = cli.Call(ctx, conn, "test_no_such_proc", nil)
+	if err == nil {
+		t.Error("wanted an error for a missing procedure")
+	}
+
+	// a procedure with the same name in a schema outside CURRENT PATH
+	_, err = db.Exec(`CREATE OR REPLACE PROCEDURE call_other.test_call(IN p_x INT)
+	LANGUAGE SQL
+	BEGIN
+	END`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP SCHEMA call_other RESTRICT")
+	defer db.Exec("DROP PROCEDURE call_other.test_call")
+	res, err = cli.Call(ctx, conn, "test_call", map[string]interface{}{"p_id": 7, "p_count": 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.Out["P_NAME"]; got != "name 7" {
+		t.Errorf("wanted the procedure in CURRENT PATH, got %#v", res.Out)
+	}
+
+	// a changed procedure fails once with the cached parameters
+	if _, err = db.Exec("DROP PROCEDURE test_call"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`CREATE PROCEDURE test_call(IN p_id INT, OUT p_name VARCHAR(20))
+	LANGUAGE SQL
+	BEGIN
+		SET p_name = 'new ' || VARCHAR(p_id);
+	END`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.Call(ctx, conn, "test_call", map[string]interface{}{"p_id": 8}); err == nil {
+		t.Error("wanted an error for the cached parameters of a changed procedure")
+	}
+	res, err = cli.Call(ctx, conn, "test_call", map[string]interface{}{"p_id": 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.Out["P_NAME"]; got != "new 8" {
+		t.Errorf("wanted OUT P_NAME %q, got %#v", "new 8", res.Out)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	for _, stmt := range []string{
+		"CREATE TABLE cat_parent(id INT NOT NULL PRIMARY KEY, name VARCHAR(20) DEFAULT 'none')",
+		"CREATE TABLE cat_child(id INT NOT NULL PRIMARY KEY, parent_id INT REFERENCES cat_parent ON DELETE CASCADE)",
+		"CREATE INDEX cat_child_ix ON cat_child(parent_id DESC)",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer db.Exec("DROP TABLE cat_parent")
+	defer db.Exec("DROP TABLE cat_child")
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var schema string
+	err = conn.QueryRowContext(ctx, "VALUES CURRENT SCHEMA").Scan(&schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema = strings.TrimSpace(schema)
+
+	tables, err := cli.Tables(ctx, conn, schema, "CAT\\_%", "TABLE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 2 || tables[0].Name != "CAT_CHILD" || tables[1].Name != "CAT_PARENT" {
+		t.Errorf("wanted tables CAT_CHILD and CAT_PARENT, got %+v", tables)
+	}
+
+	cols, err := cli.Columns(ctx, conn, schema, "CAT_PARENT", "%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cols) != 2 {
+		t.Fatalf("wanted 2 columns, got %+v", cols)
+	}
+	if c := cols[1]; c.Name != "NAME" || c.TypeName != "VARCHAR" || c.Size != 20 ||
+		!c.Nullable || c.Default != "'none'" || c.OrdinalPosition != 2 {
+		t.Errorf("unexpected column NAME %+v", c)
+	}
+
+	keys, err := cli.PrimaryKeys(ctx, conn, schema, "CAT_PARENT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0].Column != "ID" || keys[0].KeySeq != 1 {
+		t.Errorf("wanted primary key ID, got %+v", keys)
+	}
+
+	fks, err := cli.ForeignKeys(ctx, conn, "", "", schema, "CAT_CHILD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fks) != 1 || fks[0].PKTable != "CAT_PARENT" || fks[0].PKColumn != "ID" ||
+		fks[0].FKColumn != "PARENT_ID" || fks[0].DeleteRule != "CASCADE" {
+		t.Errorf("wanted foreign key PARENT_ID to CAT_PARENT.ID, got %+v", fks)
+	}
+
+	ixs, err := cli.Indexes(ctx, conn, schema, "CAT_CHILD", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, ix := range ixs {
+		if ix.IndexName == "CAT_CHILD_IX" {
+			found = true
+			if ix.Unique || !ix.Descending || ix.Column != "PARENT_ID" {
+				t.Errorf("unexpected index column %+v", ix)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("index CAT_CHILD_IX not found in %+v", ixs)
+	}
+
+	rowid, err := cli.SpecialColumns(ctx, conn, cli.BestRowID, schema, "CAT_PARENT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rowid) != 1 || rowid[0].Name != "ID" {
+		t.Errorf("wanted row identifier ID, got %+v", rowid)
+	}
+
+	privs, err := cli.TablePrivileges(ctx, conn, schema, "CAT_PARENT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(privs) == 0 {
+		t.Error("wanted the privileges of the table owner")
+	}
+}
+
+func TestRoutines(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	_, err = db.Exec(`CREATE OR REPLACE PROCEDURE test_routine(IN p_id INT, INOUT p_amount DECIMAL(9, 2), OUT p_name VARCHAR(20))
+	LANGUAGE SQL
+	SPECIFIC test_routine_1
+	DYNAMIC RESULT SETS 2
+	BEGIN
+		SET p_name = 'x';
+	END`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP PROCEDURE test_routine")
+	_, err = db.Exec(`CREATE OR REPLACE FUNCTION test_routine_f(p_x INT, p_y VARCHAR(10))
+	RETURNS INT
+	LANGUAGE SQL
+	RETURN p_x + LENGTH(p_y)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DROP FUNCTION test_routine_f")
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	procs, err := cli.Procedures(ctx, conn, "", "TEST\\_ROUTINE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(procs) != 1 {
+		t.Fatalf("wanted procedure TEST_ROUTINE, got %+v", procs)
+	}
+	p := procs[0]
+	if p.Function || p.ResultSets != 2 {
+		t.Errorf("unexpected procedure %+v", p)
+	}
+	wantParams := []struct {
+		name      string
+		direction cli.ParamDirection
+		typeName  string
+	}{
+		{"P_ID", cli.ParamInput, "INTEGER"},
+		{"P_AMOUNT", cli.ParamInputOutput, "DECIMAL"},
+		{"P_NAME", cli.ParamOutput, "VARCHAR"},
+	}
+	if len(p.Params) != len(wantParams) {
+		t.Fatalf("wanted %d parameters, got %+v", len(wantParams), p.Params)
+	}
+	for i, want := range wantParams {
+		got := p.Params[i]
+		if got.Name != want.name || got.Direction != want.direction || got.TypeName != want.typeName ||
+			got.OrdinalPosition != i+1 {
+			t.Errorf("parameter %d: wanted %+v, got %+v", i+1, want, got)
+		}
+	}
+	if a := p.Params[1]; a.Length != 9 || a.Scale != 2 {
+		t.Errorf("wanted DECIMAL(9, 2), got %+v", a)
+	}
+
+	funcs, err := cli.Functions(ctx, conn, "", "TEST\\_ROUTINE\\_F")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(funcs) != 1 || !funcs[0].Function || len(funcs[0].Params) != 3 {
+		t.Fatalf("wanted function TEST_ROUTINE_F with 2 parameters and a return value, got %+v", funcs)
+	}
+	params := funcs[0].Params
+	if params[0].Name != "P_X" || params[1].Name != "P_Y" || params[1].Length != 10 {
+		t.Errorf("unexpected parameters %+v", params)
+	}
+	if params[2].Direction != cli.ParamReturnValue || params[2].TypeName != "INTEGER" {
+		t.Errorf("wanted an INTEGER return value, got %+v", params[2])
+	}
+	// the procedure's type codes come from SQLProcedureColumns
+	if params[0].DataType != p.Params[0].DataType || params[2].DataType != p.Params[0].DataType ||
+		params[1].DataType != p.Params[2].DataType || !params[1].Nullable {
+		t.Errorf("wanted the INTEGER and VARCHAR type codes %d and %d, got %+v",
+			p.Params[0].DataType, p.Params[2].DataType, params)
+	}
+}
+
+func TestInfo(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	info, err := cli.Info(ctx, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%s %s, driver %s %s, database code page %d",
+		info.DBMSName, info.DBMSVersion, info.DriverName, info.DriverVersion, info.DatabaseCodepage)
+	if !strings.HasPrefix(info.DBMSName, "DB2") {
+		t.Errorf("wanted a DB2 server, got %q", info.DBMSName)
+	}
+	if _, _, _, ok := info.Version(); !ok {
+		t.Errorf("can't parse server version %q", info.DBMSVersion)
+	}
+	if info.DatabaseCodepage == 0 || info.MaxColumnNameLen == 0 {
+		t.Errorf("wanted the code page and name lengths, got %+v", info)
+	}
+	if !info.HasType("VARCHAR") || info.HasType("NO_SUCH_TYPE") {
+		t.Errorf("unexpected types %+v", info.Types)
+	}
+	if info.AtLeast(11, 1) != info.HasType("BOOLEAN") {
+		t.Logf("BOOLEAN support doesn't follow the version %s", info.DBMSVersion)
+	}
+
+	// the second call uses the information of the connection
+	again, err := cli.Info(ctx, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.DBMSVersion != info.DBMSVersion || len(again.Types) != len(info.Types) {
+		t.Errorf("wanted the same information, got %+v", again)
+	}
+}
+
+func TestSPClobOut(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	_, err = db.Exec(`
+	 CREATE OR REPLACE PROCEDURE out_param_clob(IN empno char(6)
+		, IN resume_format varchar(10)
+		, OUT resume clob)
+	LANGUAGE SQL
+	SPECIFIC out_param_clob
+	BEGIN
+		select resume into resume from emp_resume
+		where empno = empno and resume_format = resume_format
+		fetch first 1 row only;
+	END
+	 `)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		db.Exec("DROP PROCEDURE out_param_clob")
+	}()
+
+	var resume string
+	procStmt := "CALL out_param_clob('000140', 'ascii', ?)"
+	_, err = db.ExecContext(context.Background(), procStmt,
+		sql.Out{Dest: &resume})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%s returned %s\n", procStmt, resume)
+}
+
+func TestSPOutTypes(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	_, err = db.Exec(`
+	 CREATE OR REPLACE PROCEDURE out_param_types(IN n integer
+		, OUT d date
+		, OUT tm time
+		, OUT dec decimal(9, 2)
+		, OUT sm smallint
+		, OUT vc varchar(10)
+		, OUT big clob(10M)
+		, OUT bin blob(10M))
+	LANGUAGE SQL
+	SPECIFIC out_param_types
+	BEGIN
+		set d = '2019-03-04';
+		set tm = '13:14:15';
+		set dec = -1234.5;
+		set sm = -7;
+		set vc = 'abcdefghij';
+		set big = repeat(clob('x'), n);
+		set bin = cast(repeat(clob('y'), n) as blob(10M));
+	END
+	 `)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		db.Exec("DROP PROCEDURE out_param_types")
+	}()
+
+	// with autocommit LOB values are read into a 1 MB buffer
+	n := 100000
+	var (
+		d, tm time.Time
+		dec   float64
+		sm    int
+		vc    string
+		big   string
+		bin   []byte
+	)
+	_, err = db.Exec("CALL out_param_types(?, ?, ?, ?, ?, ?, ?, ?)", n,
+		sql.Out{Dest: &d}, sql.Out{Dest: &tm}, sql.Out{Dest: &dec}, sql.Out{Dest: &sm},
+		sql.Out{Dest: &vc}, sql.Out{Dest: &big}, sql.Out{Dest: &bin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Year() != 2019 || d.Month() != time.March || d.Day() != 4 {
+		t.Errorf("DATE: got %v", d)
+	}
+	if tm.Hour() != 13 || tm.Minute() != 14 || tm.Second() != 15 {
+		t.Errorf("TIME: got %v", tm)
+	}
+	if dec != -1234.5 {
+		t.Errorf("DECIMAL: expected -1234.5, got %v", dec)
+	}
+	if sm != -7 {
+		t.Errorf("SMALLINT: expected -7, got %d", sm)
+	}
+	if vc != "abcdefghij" {
+		t.Errorf("VARCHAR: expected abcdefghij, got %q", vc)
+	}
+	if big != strings.Repeat("x", n) {
+		t.Errorf("CLOB: expected %d x, got %d bytes", n, len(big))
+	}
+	if !bytes.Equal(bin, bytes.Repeat([]byte("y"), n)) {
+		t.Errorf("BLOB: expected %d y, got %d bytes", n, len(bin))
+	}
+
+	// a DECIMAL keeps its digits in a string
+	var decStr string
+	_, err = db.Exec("CALL out_param_types(?, ?, ?, ?, ?, ?, ?, ?)", 1,
+		sql.Out{Dest: &d}, sql.Out{Dest: &tm}, sql.Out{Dest: &decStr}, sql.Out{Dest: &sm},
+		sql.Out{Dest: &vc}, sql.Out{Dest: &big}, sql.Out{Dest: &bin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decStr != "-1234.50" {
+		t.Errorf("DECIMAL: expected -1234.50, got %q", decStr)
+	}
+
+	// a longer LOB needs a transaction, which keeps its locator valid
+	n = 2 << 20
+	_, err = db.Exec("CALL out_param_types(?, ?, ?, ?, ?, ?, ?, ?)", n,
+		sql.Out{Dest: &d}, sql.Out{Dest: &tm}, sql.Out{Dest: &dec}, sql.Out{Dest: &sm},
+		sql.Out{Dest: &vc}, sql.Out{Dest: &big}, sql.Out{Dest: &bin})
+	if err == nil || !strings.Contains(err.Error(), "transaction") {
+		t.Errorf("expected an error for a 2 MB LOB with autocommit, got %v", err)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec("CALL out_param_types(?, ?, ?, ?, ?, ?, ?, ?)", n,
+		sql.Out{Dest: &d}, sql.Out{Dest: &tm}, sql.Out{Dest: &dec}, sql.Out{Dest: &sm},
+		sql.Out{Dest: &vc}, sql.Out{Dest: &big}, sql.Out{Dest: &bin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if big != strings.Repeat("x", n) {
+		t.Errorf("CLOB: expected %d x, got %d bytes", n, len(big))
+	}
+	if !bytes.Equal(bin, bytes.Repeat([]byte("y"), n)) {
+		t.Errorf("BLOB: expected %d y, got %d bytes", n, len(bin))
+	}
+}
+
+// To check if we can use sql.Query to run a non-select statement
+func TestDDLQuery(t *testing.T) {
+	tabname := "test"
+	createStmt := fmt.Sprintf("create table %s(col1 smallint)", tabname)
+	insertStmt := fmt.Sprintf("insert into %s values(1)", tabname)
+	selectStmt := fmt.Sprintf("select col1 from %s", tabname)
+	dropStmt := fmt.Sprintf("drop table %s", tabname)
+
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+
+	rows, err := db.Query(createStmt)
+	if err != nil {
+		die(t, "%q failed: %v\n", createStmt, err)
+	}
+	if cols, _ := rows.Columns(); len(cols) != 0 {
+		die(t, "%q: expected no columns, got %v\n", createStmt, cols)
+	}
+	if rows.Next() {
+		die(t, "%q: expected no rows\n", createStmt)
+	}
+	if err := rows.Close(); err != nil {
+		die(t, "%q: rows.Close failed: %v\n", createStmt, err)
+	}
+
+	_, err = db.Exec(insertStmt)
+	if err != nil {
+		die(t, "%q failed: %v\n", insertStmt, err)
+	}
+
+	var val int
+	err = db.QueryRow(selectStmt).Scan(&val)
+	if err != nil {
+		die(t, "%q failed: %v\n", selectStmt, err)
 	}
 	if val != 1 {
 		die(t, "Expected 1, got %d\n", val)
@@ -1224,8 +2756,8 @@ func TestDDLQuery(t *testing.T) {
 	}
 }
 
-// Tests rowsAffectedError from sql.Query for statements
-// that don't produce rows/resultset
+// Tests sql.Query for statements that don't produce rows/resultset
+// and sql.Exec for a query
 func TestQueryExec(t *testing.T) {
 	var val int
 	qry := "SELECT 1 FROM syscat.tables where tabschema='abcd'"
@@ -1269,30 +2801,77 @@ func TestQueryExec(t *testing.T) {
 		die(t, "%q: rows.Close failed: %v\n", err)
 	}
 
-	execStmts := []string{
-		"create table test(col1 smallint)",
-		"insert into test values(1), (2), (3)",
-		"drop table test",
+	info(t, "Testing Exec")
+	info(t, strings.Repeat("#", 40))
+	stmt, err := db.Prepare("SELECT 1 FROM sysibm.sysdummy1")
+	if err != nil {
+		die(t, "Prepare failed: %v\n", err)
+	}
+	defer stmt.Close()
+	// the second Exec fails if the first leaves the cursor open
+	for i := 0; i < 2; i++ {
+		res, err := stmt.Exec()
+		if err != nil {
+			die(t, "Exec of a query failed: %v\n", err)
+		}
+		if n, _ := res.RowsAffected(); n != -1 {
+			die(t, "Exec of a query: expected -1 rows affected, got %d\n", n)
+		}
+	}
+
+	execStmts := []struct {
+		stmt string
+		rows int64
+	}{
+		{"create table test(col1 smallint)", -1},
+		{"insert into test values(1), (2), (3)", 3},
+		{"drop table test", -1},
 	}
 	info(t, "Testing DDL")
 	info(t, strings.Repeat("#", 40))
 
-	type rowsaff interface {
-		RowsAffected() int64
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		die(t, "Conn failed: %v\n", err)
 	}
+	defer conn.Close()
 
 	for _, s := range execStmts {
-		_, err = db.Query(s)
-		switch {
-		case err == sql.ErrNoRows:
-			info(t, "%q returned empty result set", s)
-		default:
-			if i, ok := err.(rowsaff); ok {
-				info(t, "%q: rows affected %d\n", s, i.RowsAffected())
-			} else {
-				die(t, "%q failed: %v", s, err)
-			}
+		rows, err := conn.QueryContext(ctx, s.stmt)
+		if err != nil {
+			die(t, "%q failed: %v", s.stmt, err)
 		}
+		if rows.Next() {
+			die(t, "%q: expected no rows\n", s.stmt)
+		}
+		if err := rows.Close(); err != nil {
+			die(t, "%q: rows.Close failed: %v\n", s.stmt, err)
+		}
+		n, err := cli.RowsAffected(conn)
+		if err != nil {
+			die(t, "RowsAffected failed: %v\n", err)
+		}
+		if n != s.rows {
+			die(t, "%q: expected %d rows affected, got %d\n", s.stmt, s.rows, n)
+		}
+		info(t, "%q: rows affected %d\n", s.stmt, n)
+	}
+
+	// the driver's catalog queries keep the row count of the last statement
+	if _, err := conn.ExecContext(ctx, "declare global temporary table session.t(c1 int) on commit preserve rows"); err != nil {
+		die(t, "declare failed: %v\n", err)
+	}
+	rows, err = conn.QueryContext(ctx, "insert into session.t values(1), (2)")
+	if err != nil {
+		die(t, "insert failed: %v\n", err)
+	}
+	rows.Close()
+	if _, err := cli.Info(ctx, conn); err != nil {
+		die(t, "Info failed: %v\n", err)
+	}
+	if n, _ := cli.RowsAffected(conn); n != 2 {
+		die(t, "expected 2 rows affected after Info, got %d\n", n)
 	}
 }
 
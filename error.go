@@ -91,17 +91,17 @@ func (e *cliError) SQLCode() int {
 	return e.sqlcode
 }
 
-type rowsAffectedError struct {
-	rowsAffected int64
-}
-
-func (ra *rowsAffectedError) Error() string {
-	return fmt.Sprintf("asifjalil/cli: number of rows affected %d", ra.rowsAffected)
-}
-
-// RowsAffected returns the number of rows affected by an
-// update, insert, merge, or delete. -1 indicates a statement
-// other than update, insert, delete, or a merge.
-func (ra *rowsAffectedError) RowsAffected() int64 {
-	return ra.rowsAffected
+// diagSQLStates returns the SQLSTATE of each diagnostic record of handle h.
+func diagSQLStates(ht C.SQLSMALLINT, h C.SQLHANDLE) []string {
+	var states []string
+	sqlState := make([]uint16, 6)
+	for i := 1; ; i++ {
+		ret := C.SQLGetDiagRecW(C.SQLSMALLINT(ht), h, C.SQLSMALLINT(i),
+			(*C.SQLWCHAR)(unsafe.Pointer(&sqlState[0])), nil, nil, 0, nil)
+		if ret == C.SQL_INVALID_HANDLE || ret == C.SQL_NO_DATA || ret == C.SQL_ERROR {
+			break
+		}
+		states = append(states, utf16ToString(sqlState))
+	}
+	return states
 }
@@ -0,0 +1,121 @@
+package cli
+
+/*
+#include <sqlcli1.h>
+*/
+import "C"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unsafe"
+)
+
+// Queryer is implemented by *sql.DB, *sql.Conn and *sql.Tx.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// InsertReturning runs insert, an INSERT statement, as
+// SELECT columns FROM FINAL TABLE (insert) and returns the values of
+// columns in the inserted rows. Use it to get the generated keys of
+// a multi-row insert:
+//
+//	rows, err := cli.InsertReturning(ctx, db,
+//		"INSERT INTO orders(item) VALUES(?), (?)", []string{"id"}, "pen", "ink")
+func InsertReturning(ctx context.Context, q Queryer, insert string, columns []string, args ...interface{}) (*sql.Rows, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("database/sql/driver: [asifjalil][CLI Driver]: InsertReturning needs at least one column")
+	}
+	query := "SELECT " + strings.Join(columns, ", ") + " FROM FINAL TABLE (" + insert + ")"
+	return q.QueryContext(ctx, query, args...)
+}
+
+// isInsert reports whether query is an INSERT statement.
+func isInsert(query string) bool {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	return len(query) >= 6 && strings.EqualFold(query[:6], "INSERT")
+}
+
+// isInsertValues reports whether query is an INSERT INTO table VALUES
+// statement, the only INSERT whose identity IDENTITY_VAL_LOCAL() returns.
+func isInsertValues(query string) bool {
+	if !isInsert(query) {
+		return false
+	}
+	q := strings.TrimSpace(strings.TrimLeft(query, " \t\r\n(")[6:])
+	if len(q) < 4 || !strings.EqualFold(q[:4], "INTO") {
+		return false
+	}
+	q = strings.TrimSpace(q[4:])
+	// the table name, which can be delimited
+	quoted := false
+	i := 0
+	for ; i < len(q); i++ {
+		c := q[i]
+		if c == '"' {
+			quoted = !quoted
+		} else if !quoted && (c == '(' || c == ' ' || c == '\t' || c == '\r' || c == '\n') {
+			break
+		}
+	}
+	q = strings.TrimSpace(q[i:])
+	// the column list
+	if strings.HasPrefix(q, "(") {
+		depth := 0
+		quoted = false
+		for i = 0; i < len(q); i++ {
+			switch c := q[i]; {
+			case c == '"':
+				quoted = !quoted
+			case quoted:
+			case c == '(':
+				depth++
+			case c == ')':
+				depth--
+			}
+			if depth == 0 {
+				break
+			}
+		}
+		if i == len(q) {
+			return false
+		}
+		q = strings.TrimSpace(q[i+1:])
+	}
+	return len(q) >= 6 && strings.EqualFold(q[:6], "VALUES") &&
+		(len(q) == 6 || !isNameChar(q[6]))
+}
+
+// identityValLocal returns IDENTITY_VAL_LOCAL(), the identity value
+// assigned by the last single-row INSERT on the connection.
+func (c *conn) identityValLocal() (int64, error) {
+	var h C.SQLHANDLE
+	ret := C.SQLAllocHandle(C.SQL_HANDLE_STMT, c.hdbc, &h)
+	if !success(ret) {
+		return 0, formatError(C.SQL_HANDLE_DBC, c.hdbc)
+	}
+	defer C.SQLFreeHandle(C.SQL_HANDLE_STMT, h)
+
+	ret = C.SQLExecDirectW(C.SQLHSTMT(h),
+		(*C.SQLWCHAR)(unsafe.Pointer(stringToUTF16Ptr("VALUES IDENTITY_VAL_LOCAL()"))), C.SQL_NTS)
+	if !success(ret) {
+		return 0, formatError(C.SQL_HANDLE_STMT, h)
+	}
+	ret = C.SQLFetch(C.SQLHSTMT(h))
+	if !success(ret) {
+		return 0, formatError(C.SQL_HANDLE_STMT, h)
+	}
+	var id int64
+	var ind C.SQLLEN
+	ret = C.SQLGetData(C.SQLHSTMT(h), 1, C.SQL_C_SBIGINT,
+		C.SQLPOINTER(unsafe.Pointer(&id)), C.SQLLEN(unsafe.Sizeof(id)), &ind)
+	if !success(ret) {
+		return 0, formatError(C.SQL_HANDLE_STMT, h)
+	}
+	if ind == C.SQL_NULL_DATA {
+		return 0, errors.New("database/sql/driver: [asifjalil][CLI Driver]: the statement didn't assign an identity value")
+	}
+	return id, nil
+}
@@ -0,0 +1,25 @@
+package cli
+
+import "testing"
+
+func TestIsInsertValues(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"INSERT INTO t VALUES(1)", true},
+		{"insert into s.t(a, b) values (?, ?)", true},
+		{` INSERT INTO "my (t)"("a b") VALUES(?)`, true},
+		{"INSERT INTO t\n\tVALUES\n(?)", true},
+		{"INSERT INTO t SELECT * FROM u", false},
+		{"INSERT INTO t(a) SELECT a FROM u WHERE a IN (VALUES 1)", false},
+		{"INSERT INTO t(a) WITH x(a) AS (VALUES 1) SELECT a FROM x", false},
+		{"INSERT INTO t VALUESX", false},
+		{"UPDATE t SET a = 1", false},
+	}
+	for _, tc := range tests {
+		if got := isInsertValues(tc.query); got != tc.want {
+			t.Errorf("%q: wanted %v, got %v", tc.query, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,398 @@
+package cli
+
+/*
+#include <sqlcli1.h>
+*/
+import "C"
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// The catalog functions take schema, table and column names as they are
+// stored in the catalog, usually upper case. An empty name matches all.
+// A pattern argument can have the wildcards _ and %.
+
+// Table is a table, view, alias or other table type from SQLTables.
+type Table struct {
+	Schema  string
+	Name    string
+	Type    string // for example TABLE, VIEW, ALIAS or SYSTEM TABLE
+	Remarks string
+}
+
+// TableColumn is a column of a table from SQLColumns.
+type TableColumn struct {
+	Schema          string
+	Table           string
+	Name            string
+	DataType        int    // SQL data type code
+	TypeName        string // DB2 data type name, for example VARCHAR
+	Size            int64  // precision of a numeric column; length of other columns
+	DecimalDigits   int64  // scale of a numeric column
+	Nullable        bool
+	Default         string // default value as SQL text; empty if the column has no default
+	Remarks         string
+	OrdinalPosition int // 1 based
+}
+
+// KeyColumn is a column of a primary key from SQLPrimaryKeys.
+type KeyColumn struct {
+	Schema  string
+	Table   string
+	Column  string
+	KeySeq  int    // 1 based position of the column in the key
+	KeyName string // constraint name
+}
+
+// ForeignKey is a column of a foreign key from SQLForeignKeys.
+type ForeignKey struct {
+	PKSchema   string
+	PKTable    string
+	PKColumn   string
+	FKSchema   string
+	FKTable    string
+	FKColumn   string
+	KeySeq     int    // 1 based position of the column in the key
+	UpdateRule string // CASCADE, RESTRICT, SET NULL, NO ACTION or SET DEFAULT
+	DeleteRule string
+	FKName     string
+	PKName     string
+}
+
+// IndexColumn is a column of an index from SQLStatistics.
+type IndexColumn struct {
+	Schema          string
+	Table           string
+	IndexSchema     string
+	IndexName       string
+	Unique          bool
+	OrdinalPosition int // 1 based position of the column in the index
+	Column          string
+	Descending      bool
+	Cardinality     int64 // number of unique values in the index, or -1 if unknown
+	Pages           int64 // number of pages of the index, or -1 if unknown
+}
+
+// SpecialColumnType selects the columns SpecialColumns returns.
+type SpecialColumnType int
+
+// Special column types.
+const (
+	// BestRowID selects the columns that best identify a row.
+	BestRowID SpecialColumnType = C.SQL_BEST_ROWID
+	// RowVersion selects the columns that are updated when a row is updated.
+	RowVersion SpecialColumnType = C.SQL_ROWVER
+)
+
+// SpecialColumn is a row identifier or row version column from SQLSpecialColumns.
+type SpecialColumn struct {
+	Name          string
+	DataType      int    // SQL data type code
+	TypeName      string // DB2 data type name
+	Size          int64
+	DecimalDigits int64
+	Pseudo        bool // true for a pseudo column such as ROWID
+}
+
+// TablePrivilege is a privilege on a table from SQLTablePrivileges.
+type TablePrivilege struct {
+	Schema    string
+	Table     string
+	Grantor   string
+	Grantee   string
+	Privilege string // for example SELECT, INSERT or CONTROL
+	Grantable bool
+}
+
+// withConn calls f with the cli connection of dbConn.
+func withConn(ctx context.Context, dbConn *sql.Conn, name string, f func(c *conn) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return dbConn.Raw(func(dc interface{}) error {
+		c, ok := dc.(*conn)
+		if !ok {
+			return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: %s needs a cli connection", name)
+		}
+		return f(c)
+	})
+}
+
+// Tables returns the tables whose schema and name match the schema and
+// table patterns. types lists the table types to return, for example
+// "TABLE" and "VIEW"; no types returns all.
+//
+//	conn, err := db.Conn(ctx)
+//	...
+//	tables, err := cli.Tables(ctx, conn, "HR", "%", "TABLE")
+func Tables(ctx context.Context, dbConn *sql.Conn, schema, table string, types ...string) ([]Table, error) {
+	var data [][]interface{}
+	err := withConn(ctx, dbConn, "Tables", func(c *conn) (err error) {
+		_, data, err = c.catalogQuery(func(h C.SQLHSTMT) C.SQLRETURN {
+			s, sl := catalogArg(schema)
+			t, tl := catalogArg(table)
+			y, yl := catalogArg(strings.Join(types, ","))
+			return C.SQLTablesW(h, nil, 0, s, sl, t, tl, y, yl)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]Table, len(data))
+	for i, row := range data {
+		tables[i] = Table{
+			Schema:  catalogString(row[1]),
+			Name:    catalogString(row[2]),
+			Type:    catalogString(row[3]),
+			Remarks: catalogString(row[4]),
+		}
+	}
+	return tables, nil
+}
+
+// Columns returns the columns whose schema, table and name match the
+// schema, table and column patterns, in table and column order.
+func Columns(ctx context.Context, dbConn *sql.Conn, schema, table, column string) ([]TableColumn, error) {
+	var data [][]interface{}
+	err := withConn(ctx, dbConn, "Columns", func(c *conn) (err error) {
+		_, data, err = c.catalogQuery(func(h C.SQLHSTMT) C.SQLRETURN {
+			s, sl := catalogArg(schema)
+			t, tl := catalogArg(table)
+			n, nl := catalogArg(column)
+			return C.SQLColumnsW(h, nil, 0, s, sl, t, tl, n, nl)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	cols := make([]TableColumn, len(data))
+	for i, row := range data {
+		cols[i] = TableColumn{
+			Schema:          catalogString(row[1]),
+			Table:           catalogString(row[2]),
+			Name:            catalogString(row[3]),
+			DataType:        int(asInt64(row[4])),
+			TypeName:        catalogString(row[5]),
+			Size:            asInt64(row[6]),
+			DecimalDigits:   asInt64(row[8]),
+			Nullable:        asInt64(row[10]) == C.SQL_NULLABLE,
+			Remarks:         catalogString(row[11]),
+			Default:         catalogString(row[12]),
+			OrdinalPosition: int(asInt64(row[16])),
+		}
+	}
+	return cols, nil
+}
+
+// PrimaryKeys returns the primary key columns of table in key order.
+// schema and table are names, not patterns.
+func PrimaryKeys(ctx context.Context, dbConn *sql.Conn, schema, table string) ([]KeyColumn, error) {
+	var data [][]interface{}
+	err := withConn(ctx, dbConn, "PrimaryKeys", func(c *conn) (err error) {
+		_, data, err = c.catalogQuery(func(h C.SQLHSTMT) C.SQLRETURN {
+			s, sl := catalogArg(schema)
+			t, tl := catalogArg(table)
+			return C.SQLPrimaryKeysW(h, nil, 0, s, sl, t, tl)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]KeyColumn, len(data))
+	for i, row := range data {
+		keys[i] = KeyColumn{
+			Schema:  catalogString(row[1]),
+			Table:   catalogString(row[2]),
+			Column:  catalogString(row[3]),
+			KeySeq:  int(asInt64(row[4])),
+			KeyName: catalogString(row[5]),
+		}
+	}
+	return keys, nil
+}
+
+// ForeignKeys returns the foreign key columns that reference the primary key
+// of pkTable, the foreign key columns of fkTable, or with both tables the
+// foreign key columns of fkTable that reference pkTable. The arguments
+// are names, not patterns.
+func ForeignKeys(ctx context.Context, dbConn *sql.Conn, pkSchema, pkTable, fkSchema, fkTable string) ([]ForeignKey, error) {
+	var data [][]interface{}
+	err := withConn(ctx, dbConn, "ForeignKeys", func(c *conn) (err error) {
+		_, data, err = c.catalogQuery(func(h C.SQLHSTMT) C.SQLRETURN {
+			ps, psl := catalogArg(pkSchema)
+			pt, ptl := catalogArg(pkTable)
+			fs, fsl := catalogArg(fkSchema)
+			ft, ftl := catalogArg(fkTable)
+			return C.SQLForeignKeysW(h, nil, 0, ps, psl, pt, ptl, nil, 0, fs, fsl, ft, ftl)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]ForeignKey, len(data))
+	for i, row := range data {
+		keys[i] = ForeignKey{
+			PKSchema:   catalogString(row[1]),
+			PKTable:    catalogString(row[2]),
+			PKColumn:   catalogString(row[3]),
+			FKSchema:   catalogString(row[5]),
+			FKTable:    catalogString(row[6]),
+			FKColumn:   catalogString(row[7]),
+			KeySeq:     int(asInt64(row[8])),
+			UpdateRule: referentialRule(row[9]),
+			DeleteRule: referentialRule(row[10]),
+			FKName:     catalogString(row[11]),
+			PKName:     catalogString(row[12]),
+		}
+	}
+	return keys, nil
+}
+
+// referentialRule returns the name of an UPDATE_RULE or DELETE_RULE value.
+func referentialRule(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	switch asInt64(v) {
+	case C.SQL_CASCADE:
+		return "CASCADE"
+	case C.SQL_RESTRICT:
+		return "RESTRICT"
+	case C.SQL_SET_NULL:
+		return "SET NULL"
+	case C.SQL_NO_ACTION:
+		return "NO ACTION"
+	case C.SQL_SET_DEFAULT:
+		return "SET DEFAULT"
+	}
+	return ""
+}
+
+// Indexes returns the index columns of table in index and column order.
+// With unique only the unique indexes are returned. schema and table are
+// names, not patterns.
+func Indexes(ctx context.Context, dbConn *sql.Conn, schema, table string, unique bool) ([]IndexColumn, error) {
+	var data [][]interface{}
+	err := withConn(ctx, dbConn, "Indexes", func(c *conn) (err error) {
+		_, data, err = c.catalogQuery(func(h C.SQLHSTMT) C.SQLRETURN {
+			s, sl := catalogArg(schema)
+			t, tl := catalogArg(table)
+			var which C.SQLUSMALLINT = C.SQL_INDEX_ALL
+			if unique {
+				which = C.SQL_INDEX_UNIQUE
+			}
+			return C.SQLStatisticsW(h, nil, 0, s, sl, t, tl, which, C.SQL_QUICK)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	var cols []IndexColumn
+	for _, row := range data {
+		if asInt64(row[6]) == C.SQL_TABLE_STAT {
+			// statistics of the table, not an index
+			continue
+		}
+		cols = append(cols, IndexColumn{
+			Schema:          catalogString(row[1]),
+			Table:           catalogString(row[2]),
+			Unique:          asInt64(row[3]) == C.SQL_FALSE,
+			IndexSchema:     catalogString(row[4]),
+			IndexName:       catalogString(row[5]),
+			OrdinalPosition: int(asInt64(row[7])),
+			Column:          catalogString(row[8]),
+			Descending:      catalogString(row[9]) == "D",
+			Cardinality:     asInt64OrUnknown(row[10]),
+			Pages:           asInt64OrUnknown(row[11]),
+		})
+	}
+	return cols, nil
+}
+
+// SpecialColumns returns the columns of table that best identify a row
+// or that are updated when a row is updated. schema and table are names,
+// not patterns.
+func SpecialColumns(ctx context.Context, dbConn *sql.Conn, typ SpecialColumnType, schema, table string) ([]SpecialColumn, error) {
+	var data [][]interface{}
+	err := withConn(ctx, dbConn, "SpecialColumns", func(c *conn) (err error) {
+		_, data, err = c.catalogQuery(func(h C.SQLHSTMT) C.SQLRETURN {
+			s, sl := catalogArg(schema)
+			t, tl := catalogArg(table)
+			return C.SQLSpecialColumnsW(h, C.SQLUSMALLINT(typ), nil, 0, s, sl, t, tl,
+				C.SQL_SCOPE_CURROW, C.SQL_NULLABLE)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	cols := make([]SpecialColumn, len(data))
+	for i, row := range data {
+		cols[i] = SpecialColumn{
+			Name:          catalogString(row[1]),
+			DataType:      int(asInt64(row[2])),
+			TypeName:      catalogString(row[3]),
+			Size:          asInt64(row[4]),
+			DecimalDigits: asInt64(row[6]),
+			Pseudo:        asInt64(row[7]) == C.SQL_PC_PSEUDO,
+		}
+	}
+	return cols, nil
+}
+
+// TablePrivileges returns the privileges on the tables whose schema and
+// name match the schema and table patterns.
+func TablePrivileges(ctx context.Context, dbConn *sql.Conn, schema, table string) ([]TablePrivilege, error) {
+	var data [][]interface{}
+	err := withConn(ctx, dbConn, "TablePrivileges", func(c *conn) (err error) {
+		_, data, err = c.catalogQuery(func(h C.SQLHSTMT) C.SQLRETURN {
+			s, sl := catalogArg(schema)
+			t, tl := catalogArg(table)
+			return C.SQLTablePrivilegesW(h, nil, 0, s, sl, t, tl)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	privs := make([]TablePrivilege, len(data))
+	for i, row := range data {
+		privs[i] = TablePrivilege{
+			Schema:    catalogString(row[1]),
+			Table:     catalogString(row[2]),
+			Grantor:   catalogString(row[3]),
+			Grantee:   catalogString(row[4]),
+			Privilege: catalogString(row[5]),
+			Grantable: catalogString(row[6]) == "YES",
+		}
+	}
+	return privs, nil
+}
+
+// catalogString returns a character value of a catalog result set;
+// null is returned as an empty string.
+func catalogString(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	}
+	return ""
+}
+
+// asInt64OrUnknown is asInt64 with null returned as -1.
+func asInt64OrUnknown(v interface{}) int64 {
+	if v == nil {
+		return -1
+	}
+	return asInt64(v)
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DB2 CLI only has ? parameter markers. The driver rewrites the named markers
+// :name and @name to ? before the statement is prepared and binds
+// sql.Named arguments to them by name. A ? marker after "name =>" in a
+// CALL such as CALL proc(p_out => ?) is named after the procedure parameter.
+//
+// A marker name starts with a letter or _ and goes on with letters, digits,
+// _, $, # and @, so a : or @ right after such a character, as in the column
+// A@B, isn't a marker. CREATE and ALTER statements aren't rewritten: the body
+// of a procedure or trigger has no markers, but can have : and @.
+
+// marker is a parameter marker of a statement.
+type marker struct {
+	name string // empty for an unnamed ? marker
+	// true for :name and @name, which need named arguments
+	named bool
+}
+
+// arrowName matches the procedure parameter name before => at the end of a statement prefix.
+var arrowName = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_$#@]*)\s*=>\s*$`)
+
+// parseMarkers replaces the named parameter markers in query with ? and
+// returns the rewritten query and its markers in order. String literals,
+// delimited identifiers and comments are copied as is.
+func parseMarkers(query string) (string, []marker) {
+	named := !isDDL(query)
+	var b strings.Builder
+	var markers []marker
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			// literal or delimited identifier; a doubled quote is an escaped quote
+			j := i + 1
+			for j < len(query) {
+				if query[j] == c {
+					if j+1 < len(query) && query[j+1] == c {
+						j += 2
+						continue
+					}
+					j++
+					break
+				}
+				j++
+			}
+			b.WriteString(query[i:j])
+			i = j
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			j := strings.IndexByte(query[i:], '\n')
+			if j < 0 {
+				j = len(query) - i
+			}
+			b.WriteString(query[i : i+j])
+			i += j
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			j := strings.Index(query[i+2:], "*/")
+			if j < 0 {
+				j = len(query) - i
+			} else {
+				j += 4
+			}
+			b.WriteString(query[i : i+j])
+			i += j
+		case c == '?':
+			m := marker{}
+			prefix := b.String()
+			if len(prefix) > 300 {
+				// room for a 128 byte name and white space
+				prefix = prefix[len(prefix)-300:]
+			}
+			if sm := arrowName.FindStringSubmatch(prefix); sm != nil {
+				m.name = sm[1]
+			}
+			markers = append(markers, m)
+			b.WriteByte(c)
+			i++
+		case named && (c == ':' || c == '@') && i+1 < len(query) && isNameStart(query[i+1]) &&
+			(i == 0 || !isNameChar(query[i-1]) && query[i-1] != ':'):
+			j := i + 1
+			for j < len(query) && isNameChar(query[j]) {
+				j++
+			}
+			markers = append(markers, marker{name: query[i+1 : j], named: true})
+			b.WriteByte('?')
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String(), markers
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || '0' <= c && c <= '9' || c == '$' || c == '#' || c == '@'
+}
+
+// isDDL reports whether query is a CREATE or ALTER statement.
+func isDDL(query string) bool {
+	query = strings.TrimLeft(query, " \t\r\n")
+	for _, kw := range []string{"CREATE", "ALTER"} {
+		if len(query) > len(kw) && strings.EqualFold(query[:len(kw)], kw) && !isNameChar(query[len(kw)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasNamedMarkers reports whether the statement has :name or @name markers.
+func (s *stmt) hasNamedMarkers() bool {
+	for _, m := range s.markers {
+		if m.named {
+			return true
+		}
+	}
+	return false
+}
+
+// bindArgs orders args by parameter marker. Arguments without names are
+// bound by position. sql.Named arguments are bound to the markers with
+// the same name, ignoring case; every marker needs an argument and
+// every argument a marker.
+func (s *stmt) bindArgs(args []driver.NamedValue) ([]driver.Value, error) {
+	named := 0
+	for _, a := range args {
+		if a.Name != "" {
+			named++
+		}
+	}
+	if named == 0 {
+		if s.hasNamedMarkers() {
+			return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: named parameter markers need sql.Named arguments")
+		}
+		dargs := make([]driver.Value, len(args))
+		for n, param := range args {
+			dargs[n] = param.Value
+		}
+		return dargs, nil
+	}
+	if named != len(args) {
+		return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: can't mix named and positional arguments")
+	}
+
+	byName := make(map[string]int, len(args))
+	for i, a := range args {
+		name := strings.ToUpper(a.Name)
+		if _, dup := byName[name]; dup {
+			return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: duplicate named argument %q", a.Name)
+		}
+		byName[name] = i
+	}
+	used := make([]bool, len(args))
+	dargs := make([]driver.Value, len(s.markers))
+	for i, m := range s.markers {
+		if m.name == "" {
+			return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: parameter marker %d has no name for the named arguments", i+1)
+		}
+		j, ok := byName[strings.ToUpper(m.name)]
+		if !ok {
+			return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: missing named argument %q", m.name)
+		}
+		dargs[i] = args[j].Value
+		used[j] = true
+	}
+	for i, a := range args {
+		if !used[i] {
+			return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: named argument %q has no parameter marker", a.Name)
+		}
+	}
+	return dargs, nil
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMarkers(t *testing.T) {
+	tests := []struct {
+		query, want string
+		names       []string
+	}{
+		{"SELECT * FROM t WHERE a = :a AND b = @b", "SELECT * FROM t WHERE a = ? AND b = ?", []string{"a", "b"}},
+		{"VALUES(:x$1, :y#2, :z@3)", "VALUES(?, ?, ?)", []string{"x$1", "y#2", "z@3"}},
+		// : and @ inside identifiers, literals, delimited identifiers and comments
+		{`SELECT A@B, C#@D, ':x', ":y" FROM t -- :z`, `SELECT A@B, C#@D, ':x', ":y" FROM t -- :z`, nil},
+		{"VALUES(CAST(? AS INT)) /* @w */", "VALUES(CAST(? AS INT)) /* @w */", []string{""}},
+		{"CALL proc(p$out => ?, p_in => ?)", "CALL proc(p$out => ?, p_in => ?)", []string{"p$out", "p_in"}},
+		// CREATE and ALTER statements are kept
+		{"CREATE TRIGGER trg AFTER INSERT ON t FOR EACH ROW SET :new_val = @x",
+			"CREATE TRIGGER trg AFTER INSERT ON t FOR EACH ROW SET :new_val = @x", nil},
+		{"\n alter table t add column a@b int", "\n alter table t add column a@b int", nil},
+		{"CREATEX(:a)", "CREATEX(?)", []string{"a"}},
+	}
+	for _, tc := range tests {
+		got, markers := parseMarkers(tc.query)
+		if got != tc.want {
+			t.Errorf("%q: wanted %q, got %q", tc.query, tc.want, got)
+		}
+		var names []string
+		for _, m := range markers {
+			names = append(names, m.name)
+		}
+		if !reflect.DeepEqual(names, tc.names) {
+			t.Errorf("%q: wanted markers %q, got %q", tc.query, tc.names, names)
+		}
+	}
+}
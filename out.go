@@ -10,7 +10,7 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"reflect"
-	"strconv"
+	"strings"
 	"time"
 	"unsafe"
 )
@@ -20,6 +20,9 @@ import (
 // Once convertAssign is called, the data from the database
 // is copied to sql.Out's Dest.
 type out struct {
+	conn            *conn          // connection of a CURSOR parameter
+	loc             *time.Location // time zone of DATE, TIME and TIMESTAMP values
+	civil           bool           // return DATE and TIME as Date and TimeOfDay
 	sqlOut          *sql.Out
 	idx             int // 1 based
 	ctype           C.SQLSMALLINT
@@ -41,7 +44,8 @@ type out struct {
 // Go application, and after a database execution holds database result or data.
 // If the parameter type is OUTPUT only then out.data initially doesn't hold any data.
 // After database execution, it holds the database result or data.
-func newOut(hstmt C.SQLHANDLE, sqlOut *sql.Out, idx int) (*out, error) {
+func newOut(s *stmt, sqlOut *sql.Out, idx int) (*out, error) {
+	hstmt := s.hstmt
 	var ctype, sqltype, decimalDigits, nullable, inputOutputType C.SQLSMALLINT
 	var parameterSize C.SQLULEN
 	var buflen C.SQLLEN
@@ -69,8 +73,10 @@ func newOut(hstmt C.SQLHANDLE, sqlOut *sql.Out, idx int) (*out, error) {
 			}
 			// input value might be nil but the output value may not be
 			// so allocate buffer for output
-			data = make([]byte, parameterSize)
-			ctype = sqlTypeToCType(sqltype)
+			ctype, data = outBuffer(sqltype, parameterSize, decimalDigits)
+			if isLOBType(sqltype) {
+				ctype, data = lobCType(sqltype), make([]byte, maxInOutLOB)
+			}
 			buflen = C.SQLLEN(len(data))
 			plen = &ind
 		case string:
@@ -86,13 +92,20 @@ func newOut(hstmt C.SQLHANDLE, sqlOut *sql.Out, idx int) (*out, error) {
 			// Using WCHAR instead of CHAR type because Go string is utf-8 coded.
 			// That maps to WCHAR in ODBC/CLI.
 			ctype = C.SQL_C_WCHAR
-			sqltype = C.SQL_WCHAR
+			lob := isLOBType(sqltype)
+			if !lob {
+				sqltype = C.SQL_WCHAR
+			}
 			// https://www.ibm.com/support/knowledgecenter/en/SSEPGG_11.1.0/com.ibm.db2.luw.apdv.cli.doc/doc/c0006840.html
 			// The Unicode string arguments must be in UCS-2 encoding (native-endian format).
 			s16 := stringToUTF16(d)
 			b := extractUTF16Str(s16)
 			// utf16 uses 2 bytes per character, plus the 2 byte null terminator
-			data = make([]byte, (parameterSize*2)+2)
+			size := int(parameterSize)*2 + 2
+			if lob {
+				size = maxInt(len(b)+2, maxInOutLOB)
+			}
+			data = make([]byte, size)
 			if len(b) > len(data) {
 				return nil,
 					fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]:"+
@@ -119,25 +132,37 @@ func newOut(hstmt C.SQLHANDLE, sqlOut *sql.Out, idx int) (*out, error) {
 				b = 1
 			}
 			ctype = C.SQL_C_BIT
-			sqltype = C.SQL_BIT
+			sqltype, parameterSize, decimalDigits, err = s.boolParamType(idx)
+			if err != nil {
+				return nil, err
+			}
 			data = extract(unsafe.Pointer(&b), unsafe.Sizeof(b))
-			parameterSize = 1
 		case time.Time:
-			ctype = C.SQL_C_TYPE_TIMESTAMP
-			sqltype = C.SQL_TYPE_TIMESTAMP
-			y, m, day := d.Date()
-			t := sql_TIMESTAMP_STRUCT{
-				year:     C.SQLSMALLINT(y),
-				month:    C.SQLUSMALLINT(m),
-				day:      C.SQLUSMALLINT(day),
-				hour:     C.SQLUSMALLINT(d.Hour()),
-				minute:   C.SQLUSMALLINT(d.Minute()),
-				second:   C.SQLUSMALLINT(d.Second()),
-				fraction: C.SQLUINTEGER(d.Nanosecond()),
+			var precision int
+			sqltype, precision, err = s.describeTimestamp(idx)
+			if err != nil {
+				return nil, err
+			}
+			if sqltype == C.SQL_TYPE_TIMESTAMP_WITH_TIMEZONE {
+				ctype = C.SQL_C_TYPE_TIMESTAMP_EXT_TZ
+				t := timestampStructTZ(d, precision)
+				data = extract(unsafe.Pointer(&t), unsafe.Sizeof(t))
+				decimalDigits = C.SQLSMALLINT(precision)
+				parameterSize = timestampSize(precision) + 6
+				break
 			}
-			data = extract(unsafe.Pointer(&t), unsafe.Sizeof(t))
-			decimalDigits = 3
-			parameterSize = 20 + C.SQLULEN(decimalDigits)
+			d = d.In(s.conn.opts.location())
+			if precision > 9 {
+				ctype = C.SQL_C_TYPE_TIMESTAMP_EXT
+				t := timestampStructExt(d, 0)
+				data = extract(unsafe.Pointer(&t), unsafe.Sizeof(t))
+			} else {
+				ctype = C.SQL_C_TYPE_TIMESTAMP
+				t := timestampStruct(d, precision)
+				data = extract(unsafe.Pointer(&t), unsafe.Sizeof(t))
+			}
+			decimalDigits = C.SQLSMALLINT(precision)
+			parameterSize = timestampSize(precision)
 		case []byte:
 			ret := C.SQLDescribeParam(C.SQLHSTMT(hstmt), C.SQLUSMALLINT(idx+1),
 				&sqltype, &parameterSize, &decimalDigits, &nullable)
@@ -145,10 +170,21 @@ func newOut(hstmt C.SQLHANDLE, sqlOut *sql.Out, idx int) (*out, error) {
 				return nil, formatError(C.SQL_HANDLE_STMT, hstmt)
 			}
 			ctype = sqlTypeToCType(sqltype)
-			data = make([]byte, parameterSize)
+			size := int(parameterSize)
+			if isLOBType(sqltype) {
+				size = maxInt(len(d), maxInOutLOB)
+			}
+			if len(d) > size {
+				return nil,
+					fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: "+
+						"At param. index %d INOUT []byte size %d is greater than the allocated OUT buffer size %d",
+						idx+1, len(d), size)
+			}
+			data = make([]byte, size)
 			copy(data, d)
 			buflen = C.SQLLEN(len(data))
-			plen = &buflen
+			ind := C.SQLLEN(len(d))
+			plen = &ind
 		default:
 			return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: unsupported type %T in sql.Out.Dest at index %d",
 				d, idx+1)
@@ -160,19 +196,21 @@ func newOut(hstmt C.SQLHANDLE, sqlOut *sql.Out, idx int) (*out, error) {
 		if !success(ret) {
 			return nil, formatError(C.SQL_HANDLE_STMT, hstmt)
 		}
-		if sqltype == C.SQL_WCHAR {
-			// Output is a utf16 string that requires 2 bytes per character
-			// and 2 byte null terminator
-			data = make([]byte, (parameterSize*2)+2)
-		} else {
-			data = make([]byte, parameterSize)
+		ctype, data = outBuffer(sqltype, parameterSize, decimalDigits)
+		if isLocatorCType(ctype) && !s.conn.tx {
+			// With autocommit the CALL commits and DB2 frees its
+			// locators before they can be read.
+			ctype, data = lobCType(sqltype), make([]byte, maxInOutLOB)
 		}
-		ctype = sqlTypeToCType(sqltype)
 		buflen = C.SQLLEN(len(data))
-		plen = &buflen
+		var ind C.SQLLEN
+		plen = &ind
 	}
 
 	return &out{
+		conn:            s.conn,
+		loc:             s.conn.opts.location(),
+		civil:           s.conn.opts.CivilDateTime,
 		sqlOut:          sqlOut,
 		idx:             idx + 1,
 		ctype:           ctype,
@@ -187,40 +225,212 @@ func newOut(hstmt C.SQLHANDLE, sqlOut *sql.Out, idx int) (*out, error) {
 	}, nil
 }
 
+// maxInOutLOB is the smallest output buffer of an INOUT LOB parameter,
+// and the output buffer of an OUT XML parameter and of an OUT LOB
+// parameter outside a transaction, which have no locator.
+// A longer output value is an error.
+const maxInOutLOB = 1 << 20
+
+// outBuffer returns the C type and the buffer for the output value of a
+// parameter of type sqltype. A CLOB, BLOB or DBCLOB parameter returns
+// a LOB locator, because its declared size can be up to 2 GB.
+func outBuffer(sqltype C.SQLSMALLINT, size C.SQLULEN, decimalDigits C.SQLSMALLINT) (C.SQLSMALLINT, []byte) {
+	switch sqltype {
+	case C.SQL_CLOB, C.SQL_BLOB, C.SQL_DBCLOB:
+		var v C.SQLINTEGER
+		return locatorCType(sqltype), make([]byte, unsafe.Sizeof(v))
+	case C.SQL_XML:
+		return C.SQL_C_BINARY, make([]byte, maxInOutLOB)
+	case C.SQL_CHAR, C.SQL_VARCHAR, C.SQL_LONGVARCHAR:
+		// room for the null terminator
+		return C.SQL_C_CHAR, make([]byte, size+1)
+	case C.SQL_WCHAR, C.SQL_WVARCHAR, C.SQL_WLONGVARCHAR,
+		C.SQL_GRAPHIC, C.SQL_VARGRAPHIC, C.SQL_LONGVARGRAPHIC:
+		// Output is a utf16 string that requires 2 bytes per character
+		// and 2 byte null terminator
+		return C.SQL_C_WCHAR, make([]byte, (size+1)*2)
+	case C.SQL_DECIMAL, C.SQL_NUMERIC:
+		// the digits, sign, decimal point and null terminator
+		return C.SQL_C_CHAR, make([]byte, size+3)
+	case C.SQL_DECFLOAT:
+		// 34 digits with sign, decimal point, exponent such as E-6143
+		// and null terminator
+		return C.SQL_C_CHAR, make([]byte, 43)
+	case C.SQL_SMALLINT:
+		var v C.SQLSMALLINT
+		return C.SQL_C_SHORT, make([]byte, unsafe.Sizeof(v))
+	case C.SQL_INTEGER:
+		var v C.SQLINTEGER
+		return C.SQL_C_LONG, make([]byte, unsafe.Sizeof(v))
+	case C.SQL_BIGINT:
+		var v int64
+		return C.SQL_C_SBIGINT, make([]byte, unsafe.Sizeof(v))
+	case C.SQL_REAL:
+		var v float32
+		return C.SQL_C_FLOAT, make([]byte, unsafe.Sizeof(v))
+	case C.SQL_DOUBLE, C.SQL_FLOAT:
+		var v float64
+		return C.SQL_C_DOUBLE, make([]byte, unsafe.Sizeof(v))
+	case C.SQL_BOOLEAN:
+		return C.SQL_C_BIT, make([]byte, 1)
+	case C.SQL_TYPE_DATE:
+		var v sql_DATE_STRUCT
+		return C.SQL_C_TYPE_DATE, make([]byte, unsafe.Sizeof(v))
+	case C.SQL_TYPE_TIME:
+		var v sql_TIME_STRUCT
+		return C.SQL_C_TYPE_TIME, make([]byte, unsafe.Sizeof(v))
+	case C.SQL_TYPE_TIMESTAMP:
+		if decimalDigits > 9 {
+			// keep the picoseconds from truncation errors
+			var v sql_TIMESTAMP_STRUCT_EXT
+			return C.SQL_C_TYPE_TIMESTAMP_EXT, make([]byte, unsafe.Sizeof(v))
+		}
+		var v sql_TIMESTAMP_STRUCT
+		return C.SQL_C_TYPE_TIMESTAMP, make([]byte, unsafe.Sizeof(v))
+	case C.SQL_TYPE_TIMESTAMP_WITH_TIMEZONE:
+		var v sql_TIMESTAMP_STRUCT_EXT_TZ
+		return C.SQL_C_TYPE_TIMESTAMP_EXT_TZ, make([]byte, unsafe.Sizeof(v))
+	case C.SQL_CURSORHANDLE:
+		// DB2 CLI returns the statement handle of the result set
+		var h C.SQLHANDLE
+		return C.SQL_C_CURSORHANDLE, make([]byte, unsafe.Sizeof(h))
+	}
+	return sqlTypeToCType(sqltype), make([]byte, size)
+}
+
+// isLOBType reports whether sqltype is a LOB type whose declared size
+// can be up to 2 GB.
+func isLOBType(sqltype C.SQLSMALLINT) bool {
+	switch sqltype {
+	case C.SQL_CLOB, C.SQL_BLOB, C.SQL_DBCLOB, C.SQL_XML:
+		return true
+	}
+	return false
+}
+
+// isLocatorCType reports whether ctype is the C type of a LOB locator.
+func isLocatorCType(ctype C.SQLSMALLINT) bool {
+	switch ctype {
+	case C.SQL_C_CLOB_LOCATOR, C.SQL_C_BLOB_LOCATOR, C.SQL_C_DBCLOB_LOCATOR:
+		return true
+	}
+	return false
+}
+
+// locatorCType returns the C type of a locator for the LOB type sqltype.
+func locatorCType(sqltype C.SQLSMALLINT) C.SQLSMALLINT {
+	switch sqltype {
+	case C.SQL_BLOB:
+		return C.SQL_C_BLOB_LOCATOR
+	case C.SQL_DBCLOB:
+		return C.SQL_C_DBCLOB_LOCATOR
+	}
+	return C.SQL_C_CLOB_LOCATOR
+}
+
+// lobCType returns the C type of a buffer for the LOB type sqltype.
+func lobCType(sqltype C.SQLSMALLINT) C.SQLSMALLINT {
+	switch sqltype {
+	case C.SQL_BLOB, C.SQL_XML:
+		return C.SQL_C_BINARY
+	}
+	return C.SQL_C_WCHAR
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// length returns the length of the value in data from the indicator,
+// or the size of data if the indicator has no length.
+func (o *out) length() int {
+	if o.plen == nil || *o.plen < 0 || int(*o.plen) > len(o.data) {
+		return len(o.data)
+	}
+	return int(*o.plen)
+}
+
+// truncated reports whether the output value is longer than its buffer.
+func (o *out) truncated() bool {
+	switch o.ctype {
+	case C.SQL_C_CHAR, C.SQL_C_WCHAR, C.SQL_C_DBCHAR, C.SQL_C_BINARY:
+		return o.plen != nil && *o.plen >= 0 &&
+			int(*o.plen) > len(o.data)-nullTermSize(o.ctype)
+	}
+	return false
+}
+
 // value converts database data to driver.Value.
 func (o *out) value() (driver.Value, error) {
-	var p unsafe.Pointer
-	buf := o.data
-
-	if (o.plen != nil && *(o.plen) == C.SQL_NULL_DATA) || len(buf) == 0 {
+	if o.plen != nil && *o.plen == C.SQL_NULL_DATA {
 		return nil, nil
 	}
-	p = unsafe.Pointer(&buf[0])
+	if o.truncated() {
+		hint := ""
+		if o.inputOutputType == C.SQL_PARAM_OUTPUT && o.sqltype != C.SQL_XML && isLOBType(o.sqltype) {
+			hint = "; run the CALL in a transaction to read a longer LOB through its locator"
+		}
+		return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: "+
+			"At OUTPUT param index %d the value size %d is greater than the allocated OUT buffer size %d%s",
+			o.idx, *o.plen, len(o.data)-nullTermSize(o.ctype), hint)
+	}
+	buf := o.data[:o.length()]
+	ctype := o.ctype
+	if isLocatorCType(ctype) {
+		var err error
+		buf, ctype, err = o.readLocator()
+		if err != nil {
+			return nil, err
+		}
+	}
 
+	switch ctype {
+	case C.SQL_C_CHAR:
+		if i := bytes.IndexByte(buf, 0); i >= 0 {
+			// no length; the value is null terminated
+			buf = buf[:i]
+		}
+		// DECIMAL and DECFLOAT are fetched as CHAR and returned as
+		// a string to keep their precision; convertAssign converts
+		// the string to the type of Dest.
+		if o.sqltype == C.SQL_DECFLOAT || o.sqltype == C.SQL_DECIMAL || o.sqltype == C.SQL_NUMERIC {
+			return strings.TrimSpace(string(buf)), nil
+		}
+		return string(buf), nil
+	case C.SQL_C_WCHAR, C.SQL_C_DBCHAR:
+		if len(buf) < 2 {
+			return "", nil
+		}
+		n := len(buf) / 2
+		s := (*[1 << 28]uint16)(unsafe.Pointer(&buf[0]))[:n:n]
+		return string(utf16ToUTF8(s)), nil
+	case C.SQL_C_BINARY:
+		// The buffer is sized for the parameter, and the
+		// returned data can be shorter. Trimming null bytes
+		// would corrupt FOR BIT DATA values such as UUIDs.
+		return cloneBytes(buf), nil
+	}
+
+	if len(o.data) == 0 {
+		return nil, nil
+	}
+	p := unsafe.Pointer(&o.data[0])
 	switch o.ctype {
 	case C.SQL_C_BIT:
-		return buf[0] != 0, nil
-	case C.SQL_C_SHORT, C.SQL_C_LONG:
+		return o.data[0] != 0, nil
+	case C.SQL_C_SHORT:
+		return int32(*((*int16)(p))), nil
+	case C.SQL_C_LONG:
 		return *((*int32)(p)), nil
 	case C.SQL_C_SBIGINT:
 		return *((*int64)(p)), nil
-	case C.SQL_C_DOUBLE, C.SQL_C_FLOAT:
+	case C.SQL_C_DOUBLE:
 		return *((*float64)(p)), nil
-	case C.SQL_C_CHAR:
-		// handle DECFLOAT whose default C type is CHAR
-		if o.sqltype == C.SQL_DECFLOAT || o.sqltype == C.SQL_DECIMAL || o.sqltype == C.SQL_NUMERIC {
-			s := string(buf[:o.buflen])
-			f, err := strconv.ParseFloat(s, 64)
-			return f, err
-		}
-		return buf[:o.buflen], nil
-	case C.SQL_C_WCHAR, C.SQL_C_DBCHAR:
-		if p == nil {
-			return nil, nil
-		}
-		s := (*[1 << 28]uint16)(p)[: len(buf)/2 : len(buf)/2]
-		// s := (*[1 << 20]uint16)(p)[:len(buf)/2]
-		return utf16ToUTF8(s), nil
+	case C.SQL_C_FLOAT:
+		return float64(*((*float32)(p))), nil
 	case C.SQL_C_TYPE_TIMESTAMP:
 		t := (*sql_TIMESTAMP_STRUCT)(p)
 		r := time.Date(int(t.year),
@@ -230,38 +440,102 @@ func (o *out) value() (driver.Value, error) {
 			int(t.minute),
 			int(t.second),
 			int(t.fraction),
-			time.Local)
+			o.loc)
 		return r, nil
+	case C.SQL_C_TYPE_TIMESTAMP_EXT:
+		t := (*sql_TIMESTAMP_STRUCT_EXT)(p)
+		r := time.Date(int(t.year),
+			time.Month(t.month),
+			int(t.day),
+			int(t.hour),
+			int(t.minute),
+			int(t.second),
+			int(t.fraction),
+			o.loc)
+		return r, nil
+	case C.SQL_C_TYPE_TIMESTAMP_EXT_TZ:
+		return timeFromTZ((*sql_TIMESTAMP_STRUCT_EXT_TZ)(p)), nil
 	case C.SQL_C_TYPE_DATE:
 		t := (*sql_DATE_STRUCT)(p)
+		if o.civil {
+			return Date{Year: int(t.year), Month: time.Month(t.month), Day: int(t.day)}, nil
+		}
 		r := time.Date(int(t.year),
 			time.Month(t.month),
 			int(t.day),
-			0, 0, 0, 0, time.Local)
+			0, 0, 0, 0, o.loc)
 		return r, nil
 	case C.SQL_C_TYPE_TIME:
 		t := (*sql_TIME_STRUCT)(p)
-		r := time.Date(0, 0, 0,
+		if o.civil {
+			return TimeOfDay{Hour: int(t.hour), Minute: int(t.minute), Second: int(t.second)}, nil
+		}
+		// January 1 of year 0, like time.Parse for a time without a date
+		r := time.Date(0, time.January, 1,
 			int(t.hour),
 			int(t.minute),
 			int(t.second),
 			0,
-			time.Local)
+			o.loc)
 		return r, nil
-	case C.SQL_C_BINARY:
-		// We allocated columnsized byte slice for o.data
-		// That's the max size. Returned data can be shorter
-		// than that, so remove null bytes.
-		b := bytes.Trim(buf, "\x00")
-		if len(b) == 0 {
-			return nil, nil
-		}
-		return b, nil
 	}
 	return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: unsupported ctype %d (sqltype: %d) for stored procedure OUTPUT parameter value at index %d",
 		o.ctype, o.sqltype, o.idx)
 }
 
+// readLocator reads the value of the LOB locator in data with SQLGetLength
+// and SQLGetSubString, and frees the locator. It returns the value and its
+// C type.
+func (o *out) readLocator() ([]byte, C.SQLSMALLINT, error) {
+	loc := *(*C.SQLINTEGER)(unsafe.Pointer(&o.data[0]))
+	var h C.SQLHANDLE
+	ret := C.SQLAllocHandle(C.SQL_HANDLE_STMT, o.conn.hdbc, &h)
+	if !success(ret) {
+		return nil, 0, formatError(C.SQL_HANDLE_DBC, o.conn.hdbc)
+	}
+	defer C.SQLFreeHandle(C.SQL_HANDLE_STMT, h)
+	defer freeLocator(h, o.ctype, loc)
+
+	// the length is in bytes for CLOB and BLOB, and in
+	// double-byte characters for DBCLOB
+	var n, ind C.SQLINTEGER
+	ret = C.SQLGetLength(C.SQLHSTMT(h), o.ctype, loc, &n, &ind)
+	if !success(ret) {
+		return nil, 0, formatError(C.SQL_HANDLE_STMT, h)
+	}
+	var ctype C.SQLSMALLINT = C.SQL_C_WCHAR
+	size := (int(n) + 1) * 2
+	if o.ctype == C.SQL_C_BLOB_LOCATOR {
+		ctype, size = C.SQL_C_BINARY, maxInt(int(n), 1)
+	}
+	for {
+		buf := make([]byte, size)
+		var l C.SQLINTEGER
+		ret = C.SQLGetSubString(C.SQLHSTMT(h), o.ctype, loc, 1, C.SQLUINTEGER(n),
+			ctype, C.SQLPOINTER(unsafe.Pointer(&buf[0])), C.SQLINTEGER(len(buf)), &l, &ind)
+		if !success(ret) && int(ret) != C.SQL_SUCCESS_WITH_INFO {
+			return nil, 0, formatError(C.SQL_HANDLE_STMT, h)
+		}
+		if int(l)+nullTermSize(ctype) <= len(buf) {
+			return buf[:l], ctype, nil
+		}
+		// the conversion to the C type made the value longer
+		size = int(l) + nullTermSize(ctype)
+	}
+}
+
+// freeLocator frees the LOB locator loc with FREE LOCATOR on the statement
+// handle h. Otherwise DB2 keeps it until the end of the transaction.
+func freeLocator(h C.SQLHANDLE, ctype C.SQLSMALLINT, loc C.SQLINTEGER) {
+	ret := C.SQLBindParameter(C.SQLHSTMT(h), 1, C.SQL_PARAM_INPUT, ctype, ctype,
+		0, 0, C.SQLPOINTER(unsafe.Pointer(&loc)), 0, nil)
+	if !success(ret) {
+		return
+	}
+	C.SQLExecDirectW(C.SQLHSTMT(h),
+		(*C.SQLWCHAR)(unsafe.Pointer(stringToUTF16Ptr("FREE LOCATOR ?"))), C.SQL_NTS)
+}
+
 // convertAssign copies database data at data to Dest in sql.Out.
 // It first converts the byte slice at data to driver.Value and then copies and converts to the type in sql.Out Dest.
 func (o *out) convertAssign() error {
@@ -278,10 +552,35 @@ func (o *out) convertAssign() error {
 		return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: Dest in sql.Out at OUTPUT param index %d is not a pointer", o.idx)
 	}
 
+	if o.ctype == C.SQL_C_CURSORHANDLE {
+		return o.assignCursor()
+	}
+
 	dv, err := o.value()
 	if err != nil {
 		return err
 	}
 
-	return convertAssign(o.sqlOut.Dest, dv)
+	return convertAssignIn(o.sqlOut.Dest, dv, o.loc)
+}
+
+// assignCursor sets the *Cursor in Dest to the result set of an OUT CURSOR parameter.
+func (o *out) assignCursor() error {
+	cur, ok := o.sqlOut.Dest.(*Cursor)
+	if !ok {
+		return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: Dest in sql.Out at OUTPUT param index %d is %T; a CURSOR parameter needs *cli.Cursor",
+			o.idx, o.sqlOut.Dest)
+	}
+	*cur = Cursor{}
+	if o.plen != nil && *o.plen == C.SQL_NULL_DATA {
+		// the procedure didn't open the cursor
+		return nil
+	}
+	h := *(*C.SQLHANDLE)(unsafe.Pointer(&o.data[0]))
+	c, err := newCursor(o.conn, h)
+	if err != nil {
+		return err
+	}
+	*cur = *c
+	return nil
 }
@@ -8,6 +8,10 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 	"unsafe"
 )
@@ -21,6 +25,8 @@ type param struct {
 	// When driver.Value is of type sql.Out
 	// it requires some extra processing.
 	inout *out
+	// array is a Go slice bound to an ARRAY parameter.
+	array *arrayParam
 }
 
 // bindParam binds a driver.Value (Go value) to a parameter marker in an SQL statement.
@@ -59,6 +65,22 @@ func bindParam(s *stmt, idx int, v driver.Value) (*param, error) {
 		buflen = 0
 		plen = &ind
 	case string:
+		desc, err := s.describeParam(idx)
+		if err != nil {
+			return nil, err
+		}
+		if isBinarySQLType(desc.sqltype) {
+			// FOR BIT DATA or BLOB parameter; bind the bytes of
+			// the string without a code page conversion.
+			return bindParam(s, idx, []byte(d))
+		}
+		if s.conn.opts.UTF8 {
+			ctype = C.SQL_C_CHAR
+			sqltype = C.SQL_VARCHAR
+			buf, buflen, plen = charParam(d)
+			size = paramSize(len(d))
+			break
+		}
 		var ind C.SQLLEN = C.SQL_NTS
 		ctype = C.SQL_C_WCHAR
 		sqltype = C.SQL_WCHAR
@@ -73,48 +95,243 @@ func bindParam(s *stmt, idx int, v driver.Value) (*param, error) {
 		buflen = C.SQLLEN(l)
 		// use SQL_NTS to indicate that the string is null terminated
 		plen = &ind
+	case int8:
+		b := int16(d)
+		ctype = C.SQL_C_SSHORT
+		sqltype = C.SQL_SMALLINT
+		buf = unsafe.Pointer(&b)
+		size = 2
+	case uint8:
+		b := int16(d)
+		ctype = C.SQL_C_SSHORT
+		sqltype = C.SQL_SMALLINT
+		buf = unsafe.Pointer(&b)
+		size = 2
+	case int16:
+		ctype = C.SQL_C_SSHORT
+		sqltype = C.SQL_SMALLINT
+		buf = unsafe.Pointer(&d)
+		size = 2
+	case uint16:
+		b := int32(d)
+		ctype = C.SQL_C_SLONG
+		sqltype = C.SQL_INTEGER
+		buf = unsafe.Pointer(&b)
+		size = 4
+	case int32:
+		ctype = C.SQL_C_SLONG
+		sqltype = C.SQL_INTEGER
+		buf = unsafe.Pointer(&d)
+		size = 4
+	case uint32:
+		b := int64(d)
+		ctype = C.SQL_C_SBIGINT
+		sqltype = C.SQL_BIGINT
+		buf = unsafe.Pointer(&b)
+		size = 8
+	case int:
+		b := int64(d)
+		ctype = C.SQL_C_SBIGINT
+		sqltype = C.SQL_BIGINT
+		buf = unsafe.Pointer(&b)
+		size = 8
 	case int64:
 		ctype = C.SQL_C_SBIGINT
 		sqltype = C.SQL_BIGINT
 		buf = unsafe.Pointer(&d)
 		size = 8
+	case uint:
+		return bindParam(s, idx, uint64(d))
+	case uint64:
+		if d <= math.MaxInt64 {
+			b := int64(d)
+			ctype = C.SQL_C_SBIGINT
+			sqltype = C.SQL_BIGINT
+			buf = unsafe.Pointer(&b)
+			size = 8
+			break
+		}
+		// BIGINT can't hold the value, so bind it as DECIMAL(20, 0)
+		// using its string representation.
+		var ind C.SQLLEN = C.SQL_NTS
+		b := []byte(strconv.FormatUint(d, 10) + "\x00")
+		ctype = C.SQL_C_CHAR
+		sqltype = C.SQL_DECIMAL
+		buf = unsafe.Pointer(&b[0])
+		buflen = C.SQLLEN(len(b))
+		plen = &ind
+		size = 20
 	case bool:
 		var b byte
 		if d {
 			b = 1
 		}
 		ctype = C.SQL_C_BIT
-		sqltype = C.SQL_BIT
+		var err error
+		sqltype, size, decimal, err = s.boolParamType(idx)
+		if err != nil {
+			return nil, err
+		}
 		buf = unsafe.Pointer(&b)
-		size = 1
+	case float32:
+		ctype = C.SQL_C_FLOAT
+		sqltype = C.SQL_REAL
+		buf = unsafe.Pointer(&d)
+		size = 4
 	case float64:
 		ctype = C.SQL_C_DOUBLE
 		sqltype = C.SQL_DOUBLE
 		buf = unsafe.Pointer(&d)
 		size = 8
 	case time.Time:
-		ctype = C.SQL_C_TYPE_TIMESTAMP
-		sqltype = C.SQL_TYPE_TIMESTAMP
-		y, m, day := d.Date()
-		b := sql_TIMESTAMP_STRUCT{
-			year:     C.SQLSMALLINT(y),
-			month:    C.SQLUSMALLINT(m),
-			day:      C.SQLUSMALLINT(day),
-			hour:     C.SQLUSMALLINT(d.Hour()),
-			minute:   C.SQLUSMALLINT(d.Minute()),
-			second:   C.SQLUSMALLINT(d.Second()),
-			fraction: C.SQLUINTEGER(d.Nanosecond()),
+		var precision int
+		var err error
+		sqltype, precision, err = s.describeTimestamp(idx)
+		if err != nil {
+			return nil, err
+		}
+		if sqltype == C.SQL_TYPE_TIMESTAMP_WITH_TIMEZONE {
+			// keep the time zone offset of d
+			b := timestampStructTZ(d, precision)
+			ctype = C.SQL_C_TYPE_TIMESTAMP_EXT_TZ
+			buf = unsafe.Pointer(&b)
+			decimal = C.SQLSMALLINT(precision)
+			// +hh:mm
+			size = timestampSize(precision) + 6
+			break
 		}
+		b := timestampStruct(d.In(s.conn.opts.location()), precision)
+		ctype = C.SQL_C_TYPE_TIMESTAMP
 		buf = unsafe.Pointer(&b)
 		// based on DB2 manual: SQLBindParameter
 		// The precision of a time timestamp value is the number of digits
 		// to the right of the decimal point in the string representation
 		// of a time or timestamp (for example, the scale of yyyy-mm-dd hh:mm:ss.fff is 3)
-		decimal = 3
-		size = 20 + C.SQLULEN(decimal)
+		decimal = C.SQLSMALLINT(precision)
+		size = timestampSize(precision)
+	case ExtTimestamp:
+		if d.Picosecond < 0 || d.Picosecond > 999 {
+			return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: invalid cli.ExtTimestamp picoseconds %d at index %d", d.Picosecond, idx+1)
+		}
+		b := timestampStructExt(d.Time.In(s.conn.opts.location()), d.Picosecond)
+		ctype = C.SQL_C_TYPE_TIMESTAMP_EXT
+		sqltype = C.SQL_TYPE_TIMESTAMP
+		buf = unsafe.Pointer(&b)
+		decimal = 12
+		size = timestampSize(12)
+	case Date:
+		b := sql_DATE_STRUCT{
+			year:  C.SQLSMALLINT(d.Year),
+			month: C.SQLUSMALLINT(d.Month),
+			day:   C.SQLUSMALLINT(d.Day),
+		}
+		ctype = C.SQL_C_TYPE_DATE
+		sqltype = C.SQL_TYPE_DATE
+		buf = unsafe.Pointer(&b)
+		size = 10
+	case Time:
+		t := time.Time(d).In(s.conn.opts.location())
+		b := sql_TIME_STRUCT{
+			hour:   C.SQLUSMALLINT(t.Hour()),
+			minute: C.SQLUSMALLINT(t.Minute()),
+			second: C.SQLUSMALLINT(t.Second()),
+		}
+		ctype = C.SQL_C_TYPE_TIME
+		sqltype = C.SQL_TYPE_TIME
+		buf = unsafe.Pointer(&b)
+		size = 8
+	case TimeOfDay:
+		b := sql_TIME_STRUCT{
+			hour:   C.SQLUSMALLINT(d.Hour),
+			minute: C.SQLUSMALLINT(d.Minute),
+			second: C.SQLUSMALLINT(d.Second),
+		}
+		ctype = C.SQL_C_TYPE_TIME
+		sqltype = C.SQL_TYPE_TIME
+		buf = unsafe.Pointer(&b)
+		size = 8
+	case Timestamp:
+		if d.Precision < 0 || d.Precision > 12 {
+			return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: invalid cli.Timestamp precision %d at index %d", d.Precision, idx+1)
+		}
+		b := timestampStruct(d.Time.In(s.conn.opts.location()), d.Precision)
+		ctype = C.SQL_C_TYPE_TIMESTAMP
+		sqltype = C.SQL_TYPE_TIMESTAMP
+		buf = unsafe.Pointer(&b)
+		decimal = C.SQLSMALLINT(d.Precision)
+		size = timestampSize(d.Precision)
+	case Char:
+		ctype, buf, buflen, plen = s.stringParam(string(d))
+		sqltype = C.SQL_CHAR
+		size = paramSize(len(d))
+	case Graphic:
+		ctype = C.SQL_C_WCHAR
+		sqltype = C.SQL_GRAPHIC
+		buf, buflen, plen = wcharParam(string(d))
+		// GRAPHIC length is in double-byte characters
+		// and buflen includes the null terminator.
+		size = paramSize(int(buflen)/2 - 1)
+	case CLOB:
+		ctype, buf, buflen, plen = s.stringParam(string(d))
+		sqltype = C.SQL_CLOB
+		size = paramSize(len(d))
+	case XML:
+		// XML in a binary C type is internally encoded; DB2 detects
+		// the UTF-8 encoding of a Go string.
+		b := []byte(d)
+		ctype = C.SQL_C_BINARY
+		sqltype = C.SQL_XML
+		if len(b) > 0 {
+			buf = unsafe.Pointer(&b[0])
+		}
+		buflen = C.SQLLEN(len(b))
+		plen = &buflen
+		size = C.SQLULEN(len(b))
+	case BLOB:
+		b := make([]byte, len(d))
+		copy(b, d)
+		ctype = C.SQL_C_BINARY
+		sqltype = C.SQL_BLOB
+		if len(b) > 0 {
+			buf = unsafe.Pointer(&b[0])
+		}
+		buflen = C.SQLLEN(len(b))
+		plen = &buflen
+		size = paramSize(len(b))
+	case Decimal:
+		precision, scale, ok := decimalPrecisionScale(string(d))
+		if !ok {
+			return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: invalid cli.Decimal %q at index %d", string(d), idx+1)
+		}
+		var ind C.SQLLEN = C.SQL_NTS
+		b := []byte(string(d) + "\x00")
+		ctype = C.SQL_C_CHAR
+		sqltype = C.SQL_DECIMAL
+		buf = unsafe.Pointer(&b[0])
+		buflen = C.SQLLEN(len(b))
+		plen = &ind
+		size = C.SQLULEN(precision)
+		decimal = C.SQLSMALLINT(scale)
+	case UUID:
+		b := d
+		ctype = C.SQL_C_BINARY
+		sqltype = C.SQL_BINARY
+		buf = unsafe.Pointer(&b[0])
+		buflen = C.SQLLEN(len(b))
+		plen = &buflen
+		size = C.SQLULEN(len(b))
 	case []byte:
+		desc, err := s.describeParam(idx)
+		if err != nil {
+			return nil, err
+		}
 		ctype = C.SQL_C_BINARY
 		sqltype = C.SQL_BINARY
+		if isBinarySQLType(desc.sqltype) {
+			sqltype = desc.sqltype
+		} else if len(d) > maxVarBinary {
+			sqltype = C.SQL_BLOB
+		}
 		b := make([]byte, len(d))
 		copy(b, d)
 		// handle empty binary field
@@ -125,8 +342,11 @@ func bindParam(s *stmt, idx int, v driver.Value) (*param, error) {
 		plen = &buflen
 		size = C.SQLULEN(len(b))
 	case sql.Out:
+		if isArrayDest(d.Dest) {
+			return bindArray(s, idx, d)
+		}
 		var err error
-		inout, err = newOut(s.hstmt, &d, idx)
+		inout, err = newOut(s, &d, idx)
 		if err != nil {
 			return nil, err
 		}
@@ -141,6 +361,8 @@ func bindParam(s *stmt, idx int, v driver.Value) (*param, error) {
 		}
 		buflen = inout.buflen
 		plen = inout.plen
+	case Array:
+		return bindArray(s, idx, d.Slice)
 	default:
 		return nil, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: unsupported bind param. type %T at index %d", v, idx+1)
 	}
@@ -152,3 +374,241 @@ func bindParam(s *stmt, idx int, v driver.Value) (*param, error) {
 	}
 	return &param{plen: plen, buf: buf, inout: inout}, nil
 }
+
+// nativeNumber reports whether v is a Go integer or float that bindParam binds
+// without converting it to int64 or float64 first. A pointer to such a value
+// is dereferenced; a nil pointer is returned as nil.
+func nativeNumber(v driver.Value) (driver.Value, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if !isNativeNumber(rv.Type().Elem()) {
+			return v, false
+		}
+		if rv.IsNil() {
+			return nil, true
+		}
+		return rv.Elem().Interface(), true
+	}
+	if !rv.IsValid() || !isNativeNumber(rv.Type()) {
+		return v, false
+	}
+	return v, true
+}
+
+// isNativeNumber reports whether t is one of the predeclared integer or float types.
+// Named types, such as "type Int int64", go through driver.DefaultParameterConverter.
+func isNativeNumber(t reflect.Type) bool {
+	if t.PkgPath() != "" {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// stringParam returns the C type and buffer to bind s. With the UTF8 driver
+// option, s is bound as UTF-8 SQL_C_CHAR; otherwise as UTF-16 SQL_C_WCHAR.
+func (s *stmt) stringParam(str string) (ctype C.SQLSMALLINT, buf unsafe.Pointer, buflen C.SQLLEN, plen *C.SQLLEN) {
+	if s.conn.opts.UTF8 {
+		buf, buflen, plen = charParam(str)
+		return C.SQL_C_CHAR, buf, buflen, plen
+	}
+	buf, buflen, plen = wcharParam(str)
+	return C.SQL_C_WCHAR, buf, buflen, plen
+}
+
+// charParam returns s as a null-terminated byte buffer for a SQL_C_CHAR parameter.
+func charParam(s string) (buf unsafe.Pointer, buflen C.SQLLEN, plen *C.SQLLEN) {
+	var ind C.SQLLEN = C.SQL_NTS
+	b := make([]byte, len(s)+1)
+	copy(b, s)
+	return unsafe.Pointer(&b[0]), C.SQLLEN(len(b)), &ind
+}
+
+// wcharParam returns s as a null-terminated UTF-16 buffer for a SQL_C_WCHAR parameter.
+func wcharParam(s string) (buf unsafe.Pointer, buflen C.SQLLEN, plen *C.SQLLEN) {
+	var ind C.SQLLEN = C.SQL_NTS
+	b := stringToUTF16(s)
+	// every char takes 2 bytes
+	return unsafe.Pointer(&b[0]), C.SQLLEN(len(b) * 2), &ind
+}
+
+// timestampStruct converts t to sql_TIMESTAMP_STRUCT and truncates the
+// fraction to precision digits. The struct holds nanoseconds, so digits
+// after the ninth are zero.
+func timestampStruct(t time.Time, precision int) sql_TIMESTAMP_STRUCT {
+	ns := t.Nanosecond()
+	if precision < 9 {
+		unit := int(math.Pow10(9 - precision))
+		ns -= ns % unit
+	}
+	y, m, day := t.Date()
+	return sql_TIMESTAMP_STRUCT{
+		year:     C.SQLSMALLINT(y),
+		month:    C.SQLUSMALLINT(m),
+		day:      C.SQLUSMALLINT(day),
+		hour:     C.SQLUSMALLINT(t.Hour()),
+		minute:   C.SQLUSMALLINT(t.Minute()),
+		second:   C.SQLUSMALLINT(t.Second()),
+		fraction: C.SQLUINTEGER(ns),
+	}
+}
+
+// timestampStructExt converts t and the picoseconds after its nanoseconds
+// to sql_TIMESTAMP_STRUCT_EXT.
+func timestampStructExt(t time.Time, picosecond int) sql_TIMESTAMP_STRUCT_EXT {
+	y, m, day := t.Date()
+	return sql_TIMESTAMP_STRUCT_EXT{
+		year:      C.SQLSMALLINT(y),
+		month:     C.SQLUSMALLINT(m),
+		day:       C.SQLUSMALLINT(day),
+		hour:      C.SQLUSMALLINT(t.Hour()),
+		minute:    C.SQLUSMALLINT(t.Minute()),
+		second:    C.SQLUSMALLINT(t.Second()),
+		fraction:  C.SQLUINTEGER(t.Nanosecond()),
+		fraction2: C.SQLUINTEGER(picosecond),
+	}
+}
+
+// timestampStructTZ converts t, with its time zone offset, to sql_TIMESTAMP_STRUCT_EXT_TZ
+// and truncates the fraction to precision digits.
+func timestampStructTZ(t time.Time, precision int) sql_TIMESTAMP_STRUCT_EXT_TZ {
+	ts := timestampStruct(t, precision)
+	_, offset := t.Zone()
+	tzh, tzm := offset/3600, (offset%3600)/60
+	if tzm < 0 && tzh < 0 {
+		// the sign of the offset is the sign of the hour
+		tzm = -tzm
+	}
+	return sql_TIMESTAMP_STRUCT_EXT_TZ{
+		year:            ts.year,
+		month:           ts.month,
+		day:             ts.day,
+		hour:            ts.hour,
+		minute:          ts.minute,
+		second:          ts.second,
+		fraction:        ts.fraction,
+		timezone_hour:   C.SQLSMALLINT(tzh),
+		timezone_minute: C.SQLSMALLINT(tzm),
+	}
+}
+
+// describeTimestamp returns the SQL type of the parameter at idx and its precision,
+// if it is a TIMESTAMP or TIMESTAMP WITH TIME ZONE. Otherwise it returns
+// SQL_TYPE_TIMESTAMP and the TimestampPrecision driver option.
+func (s *stmt) describeTimestamp(idx int) (C.SQLSMALLINT, int, error) {
+	desc, err := s.describeParam(idx)
+	if err != nil {
+		return 0, 0, err
+	}
+	if (desc.sqltype == C.SQL_TYPE_TIMESTAMP || desc.sqltype == C.SQL_TYPE_TIMESTAMP_WITH_TIMEZONE) &&
+		desc.decimalDigits >= 0 && desc.decimalDigits <= 12 {
+		return desc.sqltype, int(desc.decimalDigits), nil
+	}
+	return C.SQL_TYPE_TIMESTAMP, s.conn.opts.timestampPrecision(), nil
+}
+
+// boolParamType returns the SQL type, size and scale to bind a Go bool to the
+// parameter at idx. DB2 CLI converts the SQL_C_BIT value to the parameter's
+// type, so a bool works for BOOLEAN, SMALLINT, INTEGER and CHAR(1) parameters.
+func (s *stmt) boolParamType(idx int) (C.SQLSMALLINT, C.SQLULEN, C.SQLSMALLINT, error) {
+	desc, err := s.describeParam(idx)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return desc.sqltype, desc.size, desc.decimalDigits, nil
+}
+
+// maxVarBinary is the maximum length of a VARCHAR FOR BIT DATA value.
+// A longer []byte parameter is bound as a BLOB.
+const maxVarBinary = 32672
+
+// paramDesc is the result of SQLDescribeParam for a parameter.
+type paramDesc struct {
+	sqltype       C.SQLSMALLINT
+	size          C.SQLULEN
+	decimalDigits C.SQLSMALLINT
+}
+
+// describeParam calls SQLDescribeParam for the parameter at idx.
+// The result is cached because SQLDescribeParam can
+// need a round trip to the server.
+func (s *stmt) describeParam(idx int) (paramDesc, error) {
+	if d, found := s.paramDescs[idx]; found {
+		return d, nil
+	}
+	var d paramDesc
+	var nullable C.SQLSMALLINT
+	ret := C.SQLDescribeParam(C.SQLHSTMT(s.hstmt), C.SQLUSMALLINT(idx+1),
+		&d.sqltype, &d.size, &d.decimalDigits, &nullable)
+	if !success(ret) {
+		return d, formatError(C.SQL_HANDLE_STMT, s.hstmt)
+	}
+	if s.paramDescs == nil {
+		s.paramDescs = make(map[int]paramDesc)
+	}
+	s.paramDescs[idx] = d
+	return d, nil
+}
+
+// isBinarySQLType reports whether sqltype is a binary type. DB2 CLI reports
+// CHAR and VARCHAR FOR BIT DATA as SQL_BINARY and SQL_VARBINARY.
+func isBinarySQLType(sqltype C.SQLSMALLINT) bool {
+	switch sqltype {
+	case C.SQL_BINARY, C.SQL_VARBINARY, C.SQL_LONGVARBINARY, C.SQL_BLOB:
+		return true
+	}
+	return false
+}
+
+// timestampSize returns the length of the string representation of a
+// TIMESTAMP(precision) value: yyyy-mm-dd-hh.mm.ss[.fff...].
+func timestampSize(precision int) C.SQLULEN {
+	if precision == 0 {
+		return 19
+	}
+	return 20 + C.SQLULEN(precision)
+}
+
+// decimalPrecisionScale returns the number of digits and the number of
+// digits after the decimal point in s. ok is false if s is not a decimal number.
+func decimalPrecisionScale(s string) (precision, scale int, ok bool) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		s = s[1:]
+	}
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i+1:]
+	}
+	if intPart == "" && frac == "" {
+		return 0, 0, false
+	}
+	for _, r := range intPart + frac {
+		if r < '0' || r > '9' {
+			return 0, 0, false
+		}
+	}
+	precision = len(intPart) + len(frac)
+	if precision > 31 {
+		// DB2 DECIMAL precision is at most 31
+		return 0, 0, false
+	}
+	if precision == 0 {
+		precision = 1
+	}
+	return precision, len(frac), true
+}
+
+// paramSize returns n as a parameter column size.
+// The size cannot be less than 1 even for an empty value.
+func paramSize(n int) C.SQLULEN {
+	if n < 1 {
+		n = 1
+	}
+	return C.SQLULEN(n)
+}
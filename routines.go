@@ -0,0 +1,284 @@
+package cli
+
+/*
+#include <sqlcli1.h>
+*/
+import "C"
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+)
+
+// Routine is a stored procedure or a user-defined function.
+type Routine struct {
+	Schema       string
+	Name         string
+	SpecificName string // unique name of an overloaded routine; empty if unknown
+	Function     bool   // true for a function, false for a procedure
+	ResultSets   int    // maximum number of result sets of a procedure, or -1 if unknown
+	Remarks      string
+	Params       []RoutineParam
+}
+
+// RoutineParam is a parameter, a return value or a result column of a routine.
+type RoutineParam struct {
+	Name            string
+	Direction       ParamDirection
+	DataType        int    // SQL data type code; 0 for a function parameter of a structured, ARRAY or CURSOR type
+	TypeName        string // DB2 data type name, for example VARCHAR
+	Length          int64  // precision of a numeric parameter; length of other parameters
+	Scale           int64  // scale of a numeric parameter
+	Nullable        bool   // always true for a function, whose parameters and results can't be NOT NULL
+	OrdinalPosition int    // 1 based position of a parameter or a result column; 0 for a return value
+}
+
+// Procedures returns the stored procedures whose schema and name match the
+// schema and name patterns with their parameters, from SQLProcedures and
+// SQLProcedureColumns.
+//
+//	conn, err := db.Conn(ctx)
+//	...
+//	procs, err := cli.Procedures(ctx, conn, "HR", "%")
+func Procedures(ctx context.Context, dbConn *sql.Conn, schema, name string) ([]Routine, error) {
+	var names, pnames []string
+	var data, pdata [][]interface{}
+	err := withConn(ctx, dbConn, "Procedures", func(c *conn) (err error) {
+		names, data, err = c.catalogQuery(func(h C.SQLHSTMT) C.SQLRETURN {
+			s, sl := catalogArg(schema)
+			p, pl := catalogArg(name)
+			return C.SQLProceduresW(h, nil, 0, s, sl, p, pl)
+		})
+		if err != nil {
+			return err
+		}
+		pnames, pdata, err = c.catalogQuery(func(h C.SQLHSTMT) C.SQLRETURN {
+			s, sl := catalogArg(schema)
+			p, pl := catalogArg(name)
+			return C.SQLProcedureColumnsW(h, nil, 0, s, sl, p, pl, nil, 0)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// DB2 adds the SPECIFIC_NAME column to the ODBC columns,
+	// which tells the parameters of overloaded procedures apart
+	specific := columnIndex(names, "SPECIFIC_NAME")
+	pspecific := columnIndex(pnames, "SPECIFIC_NAME")
+	bySpecific := specific >= 0 && pspecific >= 0
+
+	procs := make([]Routine, len(data))
+	byName := make(map[string]int, len(data))
+	for i, row := range data {
+		procs[i] = Routine{
+			Schema:     catalogString(row[1]),
+			Name:       catalogString(row[2]),
+			ResultSets: int(asInt64OrUnknown(row[5])),
+			Remarks:    catalogString(row[6]),
+		}
+		if specific >= 0 {
+			procs[i].SpecificName = catalogString(row[specific])
+		}
+		key := procs[i].Schema + "." + procs[i].Name
+		if bySpecific {
+			key = procs[i].Schema + "." + procs[i].SpecificName
+		}
+		byName[key] = i
+	}
+	for _, row := range pdata {
+		key := catalogString(row[1]) + "." + catalogString(row[2])
+		if bySpecific {
+			key = catalogString(row[1]) + "." + catalogString(row[pspecific])
+		}
+		i, ok := byName[key]
+		if !ok {
+			continue
+		}
+		procs[i].Params = append(procs[i].Params, RoutineParam{
+			Name:            catalogString(row[3]),
+			Direction:       ParamDirection(asInt64(row[4])),
+			DataType:        int(asInt64(row[5])),
+			TypeName:        catalogString(row[6]),
+			Length:          asInt64(row[7]),
+			Scale:           asInt64(row[9]),
+			Nullable:        asInt64(row[11]) == C.SQL_NULLABLE,
+			OrdinalPosition: int(asInt64(row[17])),
+		})
+	}
+	return procs, nil
+}
+
+// sqlTypeCode returns the SQL data type code of the built-in DB2 type
+// typeName as SQLProcedureColumns reports it, or 0 for another type.
+// binary is true for a FOR BIT DATA character type.
+func sqlTypeCode(typeName string, binary bool) int {
+	var t C.SQLSMALLINT
+	switch typeName {
+	case "SMALLINT":
+		t = C.SQL_SMALLINT
+	case "INTEGER":
+		t = C.SQL_INTEGER
+	case "BIGINT":
+		t = C.SQL_BIGINT
+	case "DECIMAL":
+		t = C.SQL_DECIMAL
+	case "DECFLOAT":
+		t = C.SQL_DECFLOAT
+	case "REAL":
+		t = C.SQL_REAL
+	case "DOUBLE":
+		t = C.SQL_DOUBLE
+	case "BOOLEAN":
+		t = C.SQL_BOOLEAN
+	case "CHARACTER":
+		t = C.SQL_CHAR
+		if binary {
+			t = C.SQL_BINARY
+		}
+	case "VARCHAR":
+		t = C.SQL_VARCHAR
+		if binary {
+			t = C.SQL_VARBINARY
+		}
+	case "LONG VARCHAR":
+		t = C.SQL_LONGVARCHAR
+		if binary {
+			t = C.SQL_LONGVARBINARY
+		}
+	case "BINARY":
+		t = C.SQL_BINARY
+	case "VARBINARY":
+		t = C.SQL_VARBINARY
+	case "CLOB":
+		t = C.SQL_CLOB
+	case "GRAPHIC":
+		t = C.SQL_GRAPHIC
+	case "VARGRAPHIC":
+		t = C.SQL_VARGRAPHIC
+	case "LONG VARGRAPHIC":
+		t = C.SQL_LONGVARGRAPHIC
+	case "DBCLOB":
+		t = C.SQL_DBCLOB
+	case "BLOB":
+		t = C.SQL_BLOB
+	case "DATE":
+		t = C.SQL_TYPE_DATE
+	case "TIME":
+		t = C.SQL_TYPE_TIME
+	case "TIMESTAMP":
+		t = C.SQL_TYPE_TIMESTAMP
+	case "XML":
+		t = C.SQL_XML
+	}
+	return int(t)
+}
+
+// columnIndex returns the index of the column called name, or -1.
+func columnIndex(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// functionsQuery returns the user-defined functions and their parameters
+// from the DB2 catalog views; DB2 CLI has no catalog function for them.
+// ROWTYPE is P, O or B for an IN, OUT or INOUT parameter, and C for the
+// result. A function with CAST FROM also has its result before the cast,
+// ROWTYPE R, which is only used when there is no C row. SOURCENAME is the
+// built-in type of a distinct type, and CODEPAGE is 0 for FOR BIT DATA.
+const functionsQuery = `SELECT R.ROUTINESCHEMA, R.ROUTINENAME, R.SPECIFICNAME, R.REMARKS, R.FUNCTIONTYPE,
+	P.PARMNAME, P.ROWTYPE, P.TYPENAME, P.LENGTH, P.SCALE, P.ORDINAL,
+	P.CODEPAGE, COALESCE(D.SOURCENAME, P.TYPENAME)
+FROM SYSCAT.ROUTINES R LEFT JOIN SYSCAT.ROUTINEPARMS P
+	ON P.ROUTINESCHEMA = R.ROUTINESCHEMA AND P.SPECIFICNAME = R.SPECIFICNAME
+	AND (P.ROWTYPE IN ('P', 'O', 'B', 'C') OR P.ROWTYPE = 'R' AND NOT EXISTS (
+		SELECT 1 FROM SYSCAT.ROUTINEPARMS C
+		WHERE C.ROUTINESCHEMA = P.ROUTINESCHEMA AND C.SPECIFICNAME = P.SPECIFICNAME AND C.ROWTYPE = 'C'))
+LEFT JOIN SYSCAT.DATATYPES D
+	ON D.TYPESCHEMA = P.TYPESCHEMA AND D.TYPENAME = P.TYPENAME AND D.METATYPE = 'T'
+WHERE R.ROUTINETYPE = 'F' AND R.ROUTINESCHEMA LIKE ? ESCAPE '\' AND R.ROUTINENAME LIKE ? ESCAPE '\'
+ORDER BY R.ROUTINESCHEMA, R.ROUTINENAME, R.SPECIFICNAME,
+	CASE WHEN P.ROWTYPE IN ('C', 'R') THEN 1 ELSE 0 END, P.ORDINAL`
+
+// Functions returns the user-defined functions whose schema and name match
+// the schema and name patterns with their parameters. The return value of
+// a scalar function is a parameter with direction ParamReturnValue and the
+// columns of a table function have direction ParamResultColumn.
+// SYSCAT.ROUTINEPARMS has no nullability, so Nullable is always true; DB2
+// doesn't allow NOT NULL on a function parameter or result.
+func Functions(ctx context.Context, dbConn *sql.Conn, schema, name string) ([]Routine, error) {
+	if schema == "" {
+		schema = "%"
+	}
+	if name == "" {
+		name = "%"
+	}
+	var data [][]interface{}
+	err := withConn(ctx, dbConn, "Functions", func(c *conn) error {
+		ds, err := c.PrepareContext(ctx, functionsQuery)
+		if err != nil {
+			return err
+		}
+		s := ds.(*stmt)
+		defer s.Close()
+		dr, err := s.query(ctx, []driver.Value{schema, name})
+		if err != nil {
+			return err
+		}
+		r := dr.(*rows)
+		defer r.Close()
+		_, data, err = readRows(r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var funcs []Routine
+	for _, row := range data {
+		rschema, specific := catalogString(row[0]), catalogString(row[2])
+		n := len(funcs)
+		if n == 0 || funcs[n-1].Schema != rschema || funcs[n-1].SpecificName != specific {
+			funcs = append(funcs, Routine{
+				Schema:       rschema,
+				Name:         catalogString(row[1]),
+				SpecificName: specific,
+				Function:     true,
+				Remarks:      catalogString(row[3]),
+			})
+			n++
+		}
+		if row[6] == nil {
+			// a function without parameters
+			continue
+		}
+		p := RoutineParam{
+			Name:            catalogString(row[5]),
+			Direction:       ParamInput,
+			DataType:        sqlTypeCode(catalogString(row[12]), asInt64(row[11]) == 0),
+			TypeName:        catalogString(row[7]),
+			Length:          asInt64(row[8]),
+			Scale:           asInt64(row[9]),
+			Nullable:        true,
+			OrdinalPosition: int(asInt64(row[10])),
+		}
+		switch catalogString(row[6]) {
+		case "O":
+			p.Direction = ParamOutput
+		case "B":
+			p.Direction = ParamInputOutput
+		case "C", "R":
+			p.Direction = ParamReturnValue
+			if catalogString(row[4]) == "T" {
+				p.Direction = ParamResultColumn
+			}
+		}
+		funcs[n-1].Params = append(funcs[n-1].Params, p)
+	}
+	return funcs, nil
+}
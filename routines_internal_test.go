@@ -0,0 +1,24 @@
+package cli
+
+import "testing"
+
+func TestSQLTypeCode(t *testing.T) {
+	tests := []struct {
+		typeName string
+		binary   bool
+		want     int
+	}{
+		{"INTEGER", false, 4},
+		{"DECIMAL", false, 3},
+		{"VARCHAR", false, 12},
+		{"VARCHAR", true, -3},
+		{"CHARACTER", true, -2},
+		{"TIMESTAMP", false, 93},
+		{"CURSOR", false, 0},
+	}
+	for _, tc := range tests {
+		if got := sqlTypeCode(tc.typeName, tc.binary); got != tc.want {
+			t.Errorf("%s (binary %v): wanted %d, got %d", tc.typeName, tc.binary, tc.want, got)
+		}
+	}
+}
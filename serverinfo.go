@@ -0,0 +1,216 @@
+package cli
+
+/*
+#include <sqlcli1.h>
+*/
+import "C"
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// ServerInfo describes the database server and the DB2 CLI driver of a
+// connection, from SQLGetInfo and SQLGetTypeInfo.
+type ServerInfo struct {
+	DBMSName            string // for example DB2/LINUXX8664
+	DBMSVersion         string // for example 11.05.0800
+	DatabaseName        string
+	ServerName          string // instance name
+	DriverName          string
+	DriverVersion       string
+	DatabaseCodepage    int
+	ApplicationCodepage int
+	IdentifierQuote     string
+	MaxIdentifierLen    int // 0 if there is no limit or it is unknown
+	MaxSchemaNameLen    int
+	MaxTableNameLen     int
+	MaxColumnNameLen    int
+	MaxProcedureNameLen int
+	MaxStatementLen     int
+	Types               []TypeInfo // data types the server supports
+}
+
+// TypeInfo is a data type the server supports, from SQLGetTypeInfo.
+type TypeInfo struct {
+	TypeName      string // DB2 data type name, for example VARCHAR
+	DataType      int    // SQL data type code
+	ColumnSize    int64  // maximum precision or length
+	LiteralPrefix string // for example ' for a character type
+	LiteralSuffix string
+	CreateParams  string // parameters in the type definition, for example "LENGTH"
+	Nullable      bool
+	CaseSensitive bool
+	Unsigned      bool
+	AutoIncrement bool
+	MinScale      int
+	MaxScale      int
+}
+
+// Info returns the server information of dbConn. It is read once per
+// connection. An application can use it to log the server version
+// or to check for a feature:
+//
+//	info, err := cli.Info(ctx, conn)
+//	...
+//	if info.HasType("BOOLEAN") {
+//		...
+//	}
+func Info(ctx context.Context, dbConn *sql.Conn) (*ServerInfo, error) {
+	var info ServerInfo
+	err := withConn(ctx, dbConn, "Info", func(c *conn) error {
+		if c.info == nil {
+			i, err := c.serverInfo()
+			if err != nil {
+				return err
+			}
+			c.info = i
+		}
+		info = *c.info
+		info.Types = append([]TypeInfo(nil), c.info.Types...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Version returns the numbers of a DBMSVersion such as 11.05.0800.
+func (i *ServerInfo) Version() (major, minor, fix int, ok bool) {
+	parts := strings.Split(i.DBMSVersion, ".")
+	if len(parts) != 3 {
+		return 0, 0, 0, false
+	}
+	var n [3]int
+	for j, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return 0, 0, 0, false
+		}
+		n[j] = v
+	}
+	return n[0], n[1], n[2], true
+}
+
+// AtLeast reports whether the server version is major.minor or later.
+func (i *ServerInfo) AtLeast(major, minor int) bool {
+	ma, mi, _, ok := i.Version()
+	if !ok {
+		return false
+	}
+	return ma > major || ma == major && mi >= minor
+}
+
+// HasType reports whether the server supports the data type called name.
+func (i *ServerInfo) HasType(name string) bool {
+	for _, t := range i.Types {
+		if strings.EqualFold(t.TypeName, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// serverInfo reads the server information with SQLGetInfo and SQLGetTypeInfo.
+func (c *conn) serverInfo() (*ServerInfo, error) {
+	info := &ServerInfo{}
+	strs := []struct {
+		infoType C.SQLUSMALLINT
+		dest     *string
+	}{
+		{C.SQL_DBMS_NAME, &info.DBMSName},
+		{C.SQL_DBMS_VER, &info.DBMSVersion},
+		{C.SQL_DATABASE_NAME, &info.DatabaseName},
+		{C.SQL_SERVER_NAME, &info.ServerName},
+		{C.SQL_DRIVER_NAME, &info.DriverName},
+		{C.SQL_DRIVER_VER, &info.DriverVersion},
+		{C.SQL_IDENTIFIER_QUOTE_CHAR, &info.IdentifierQuote},
+	}
+	for _, s := range strs {
+		v, err := c.infoString(s.infoType)
+		if err != nil {
+			return nil, err
+		}
+		*s.dest = v
+	}
+
+	shorts := []struct {
+		infoType C.SQLUSMALLINT
+		dest     *int
+	}{
+		{C.SQL_MAX_IDENTIFIER_LEN, &info.MaxIdentifierLen},
+		{C.SQL_MAX_SCHEMA_NAME_LEN, &info.MaxSchemaNameLen},
+		{C.SQL_MAX_TABLE_NAME_LEN, &info.MaxTableNameLen},
+		{C.SQL_MAX_COLUMN_NAME_LEN, &info.MaxColumnNameLen},
+		{C.SQL_MAX_PROCEDURE_NAME_LEN, &info.MaxProcedureNameLen},
+	}
+	for _, s := range shorts {
+		var v C.SQLUSMALLINT
+		ret := C.SQLGetInfoW(C.SQLHDBC(c.hdbc), s.infoType, C.SQLPOINTER(unsafe.Pointer(&v)), 0, nil)
+		if !success(ret) {
+			return nil, formatError(C.SQL_HANDLE_DBC, c.hdbc)
+		}
+		*s.dest = int(v)
+	}
+
+	ints := []struct {
+		infoType C.SQLUSMALLINT
+		dest     *int
+	}{
+		{C.SQL_MAX_STATEMENT_LEN, &info.MaxStatementLen},
+		{C.SQL_DATABASE_CODEPAGE, &info.DatabaseCodepage},
+		{C.SQL_APPLICATION_CODEPAGE, &info.ApplicationCodepage},
+	}
+	for _, s := range ints {
+		var v C.SQLUINTEGER
+		ret := C.SQLGetInfoW(C.SQLHDBC(c.hdbc), s.infoType, C.SQLPOINTER(unsafe.Pointer(&v)), 0, nil)
+		if !success(ret) {
+			return nil, formatError(C.SQL_HANDLE_DBC, c.hdbc)
+		}
+		*s.dest = int(v)
+	}
+
+	_, data, err := c.catalogQuery(func(h C.SQLHSTMT) C.SQLRETURN {
+		return C.SQLGetTypeInfoW(h, C.SQL_ALL_TYPES)
+	})
+	if err != nil {
+		return nil, err
+	}
+	info.Types = make([]TypeInfo, len(data))
+	for i, row := range data {
+		info.Types[i] = TypeInfo{
+			TypeName:      catalogString(row[0]),
+			DataType:      int(asInt64(row[1])),
+			ColumnSize:    asInt64(row[2]),
+			LiteralPrefix: catalogString(row[3]),
+			LiteralSuffix: catalogString(row[4]),
+			CreateParams:  catalogString(row[5]),
+			Nullable:      asInt64(row[6]) == C.SQL_NULLABLE,
+			CaseSensitive: asInt64(row[7]) == C.SQL_TRUE,
+			Unsigned:      asInt64(row[9]) == C.SQL_TRUE,
+			AutoIncrement: asInt64(row[11]) == C.SQL_TRUE,
+			MinScale:      int(asInt64(row[13])),
+			MaxScale:      int(asInt64(row[14])),
+		}
+	}
+	return info, nil
+}
+
+// infoString returns a character value of SQLGetInfo.
+func (c *conn) infoString(infoType C.SQLUSMALLINT) (string, error) {
+	buf := make([]uint16, 256)
+	var n C.SQLSMALLINT
+	ret := C.SQLGetInfoW(C.SQLHDBC(c.hdbc), infoType,
+		C.SQLPOINTER(unsafe.Pointer(&buf[0])), C.SQLSMALLINT(len(buf)*2), &n)
+	if !success(ret) {
+		return "", formatError(C.SQL_HANDLE_DBC, c.hdbc)
+	}
+	// n is the length in bytes
+	if l := int(n) / 2; l < len(buf) {
+		buf = buf[:l]
+	}
+	return utf16ToString(buf), nil
+}
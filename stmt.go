@@ -9,8 +9,13 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"io"
 	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unsafe"
 )
 
 type stmt struct {
@@ -21,6 +26,15 @@ type stmt struct {
 	rows   bool
 	params []*param
 	cols   []*column
+	// SQLDescribeParam results by parameter index
+	paramDescs map[int]paramDesc
+	// parameter markers and their names
+	markers []marker
+	// number of result sets of the last execution; -1 if unknown
+	resultSets int
+	// OUT parameters of a CALL with result sets are assigned
+	// after the result sets are consumed
+	outPending bool
 }
 
 func (s *stmt) Close() error {
@@ -41,9 +55,15 @@ func (s *stmt) Close() error {
 	return nil
 }
 
+// NumInput returns the number of parameter markers. With deferred prepare
+// DB2 CLI counts the markers without a server round trip. If SQLNumParams
+// fails, for example because the deferred prepare failed, -1 is returned and
+// the error is reported by Exec or Query.
+// With named markers, which can be repeated, it returns -1
+// and the arguments are checked by name instead.
 func (s *stmt) NumInput() int {
 	var paramCount C.SQLSMALLINT
-	if s.closed {
+	if s.closed || s.hasNamedMarkers() {
 		return -1
 	}
 	ret := C.SQLNumParams(C.SQLHSTMT(s.hstmt), &paramCount)
@@ -60,20 +80,37 @@ func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
 // go1.8+
 // ExecContext implements driver.StmtExecContext interface
 func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
-	dargs := make([]driver.Value, len(args))
-	for n, param := range args {
-		dargs[n] = param.Value
+	dargs, err := s.bindArgs(args)
+	if err != nil {
+		return nil, err
 	}
 
 	return s.exec(ctx, dargs)
 }
 
 // CheckNamedValue implementes driver.NamedValueChecker.
+// Go integer and float types, and pointers to them, are passed to bindParam
+// as is so they are bound with a matching DB2 type instead of BIGINT or DOUBLE.
 func (s *stmt) CheckNamedValue(nv *driver.NamedValue) (err error) {
-	switch nv.Value.(type) {
+	switch v := nv.Value.(type) {
 	case sql.Out:
 		err = nil
+	case Array:
+		if !isArray(v.Slice) {
+			err = fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: cli.Array needs a slice other than []byte, got %T", v.Slice)
+		}
+	case Date, TimeOfDay, Time, Timestamp, ExtTimestamp, UUID, CLOB, BLOB, XML, Graphic, Char, Decimal:
+		// typed parameters; bindParam picks the DB2 SQL type
+		err = nil
 	default:
+		if v, ok := nativeNumber(nv.Value); ok {
+			nv.Value = v
+			return nil
+		}
+		if isArray(nv.Value) {
+			return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: unsupported type %T at param. index %d; wrap a slice for an ARRAY parameter in cli.Array",
+				nv.Value, nv.Ordinal)
+		}
 		nv.Value, err = driver.DefaultParameterConverter.ConvertValue(nv.Value)
 	}
 	return err
@@ -86,27 +123,69 @@ func (s *stmt) exec(ctx context.Context, args []driver.Value) (driver.Result, er
 		return nil, err
 	}
 
-	r, err := s.rowsAffected()
-	if err != nil {
-		return nil, err
+	var r int64 = -1
+	if s.outPending {
+		// Exec of a CALL that returned result sets
+		err = s.drainResultSets()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		r, err = s.closeQuery()
+		if err != nil {
+			return nil, err
+		}
 	}
-
-	return &result{rows: r}, nil
+	s.conn.rowsAffected = r
+
+	res := &result{rows: r}
+	if !s.conn.opts.LastInsertID {
+		res.idErr = errors.New("database/sql/driver: [asifjalil][CLI Driver]: LastInsertId needs the LastInsertID driver option")
+	} else if isInsertValues(s.sql) && r == 1 {
+		res.id, res.idErr = s.conn.identityValLocal()
+	} else if isInsert(s.sql) {
+		res.idErr = errors.New("database/sql/driver: [asifjalil][CLI Driver]: LastInsertId is only available for a single-row INSERT ... VALUES; use InsertReturning for the keys of other INSERT statements")
+	} else {
+		res.idErr = errors.New("database/sql/driver: [asifjalil][CLI Driver]: LastInsertId is only available for INSERT")
+	}
+	return res, nil
 }
 
 func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
-	return s.query(context.Background(), args)
+	return s.userQuery(context.Background(), args)
 }
 
 // go1.8+
 // QueryContext implements driver.StmtQueryContext interface
 func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
-	dargs := make([]driver.Value, len(args))
-	for n, param := range args {
-		dargs[n] = param.Value
+	dargs, err := s.bindArgs(args)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.query(ctx, dargs)
+	return s.userQuery(ctx, dargs)
+}
+
+// userQuery runs query for Query and QueryContext and keeps the row count
+// for RowsAffected. The driver's own queries, such as the catalog queries,
+// leave the row count of the application's last statement alone.
+func (s *stmt) userQuery(ctx context.Context, args []driver.Value) (driver.Rows, error) {
+	r, err := s.query(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	s.conn.rowsAffected = -1
+	if len(s.cols) == 0 {
+		// It could be a update/insert/delete/merge statement.
+		// Keep its row count for RowsAffected.
+		n, err := s.rowsAffected()
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		s.conn.rowsAffected = n
+	}
+	return r, nil
 }
 
 // query is created to handle both Query(...) and QueryContext(...)
@@ -122,7 +201,11 @@ func (s *stmt) query(ctx context.Context, args []driver.Value) (driver.Rows, err
 		return nil, err
 	}
 	s.rows = true
-	return &rows{s, true}, nil
+	if len(s.cols) == 0 {
+		// a statement other than a query returns no columns and no rows
+		s.resultSets = 0
+	}
+	return &rows{s: s}, nil
 }
 
 // sqlexec executes any prepared statement
@@ -234,22 +317,157 @@ func (s *stmt) sqlexec(ctx context.Context, args []driver.Value) error {
 		// if ret == C.SQL_NO_DATA_FOUND {
 		// may this is a searched UPDATE/DELETE and no row satisfied the search condition
 		// }
-		if !success(ret) {
-			return formatError(C.SQL_HANDLE_STMT, s.hstmt)
+		if err := s.checkExecute(ret); err != nil {
+			return err
+		}
+		if s.outPending {
+			// DB2 assigns the OUT parameters of a CALL
+			// after all its result sets are read
+			return nil
+		}
+		return s.assignOut()
+	}
+}
+
+// checkExecute checks the return code of SQLExecute and sets s.resultSets.
+// A CALL that returns result sets succeeds with SQLSTATE 0100C, whose
+// message holds the number of result sets.
+func (s *stmt) checkExecute(ret C.SQLRETURN) error {
+	// a statement other than CALL has at most one result set
+	s.resultSets = 1
+	s.outPending = false
+	call := s.isCall()
+	if call {
+		s.resultSets = 0
+	}
+	if success(ret) {
+		return nil
+	}
+	err := formatError(C.SQL_HANDLE_STMT, s.hstmt)
+	if int(ret) != C.SQL_SUCCESS_WITH_INFO {
+		return err
+	}
+	if cliErr, ok := err.(*cliError); ok && call && cliErr.sqlstate == "0100C" {
+		s.resultSets = resultSetCount(cliErr)
+		s.outPending = true
+		return nil
+	}
+	if s.prepareWarning(ret) {
+		return nil
+	}
+	return err
+}
+
+// isCall reports whether the last executed statement is a CALL.
+func (s *stmt) isCall() bool {
+	var code C.SQLINTEGER
+	ret := C.SQLGetDiagFieldW(C.SQL_HANDLE_STMT, s.hstmt, 0, C.SQL_DIAG_DYNAMIC_FUNCTION_CODE,
+		C.SQLPOINTER(unsafe.Pointer(&code)), C.SQL_IS_INTEGER, nil)
+	if success(ret) || int(ret) == C.SQL_SUCCESS_WITH_INFO {
+		return code == C.SQL_DIAG_CALL
+	}
+	q := strings.TrimLeft(s.sql, " \t\r\n{")
+	return len(q) >= 4 && strings.EqualFold(q[:4], "CALL")
+}
+
+// resultSetCount returns the number of result sets of a CALL from its
+// SQL0466W warning 'Procedure "name" returns "n" result sets', or -1 if
+// the warning is another one or its count can't be found. The message can
+// be translated, so the count is the one quoted token that is a number.
+func resultSetCount(e *cliError) int {
+	if e.sqlcode != 466 {
+		return -1
+	}
+	n := -1
+	for _, m := range quotedNumber.FindAllStringSubmatch(e.message, -1) {
+		v, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
 		}
-		for _, p := range s.params {
-			if p.inout != nil {
-				err := p.inout.convertAssign()
-				if err != nil {
-					return err
-				}
+		if n >= 0 {
+			// a procedure name that is a number too
+			return -1
+		}
+		n = v
+	}
+	return n
+}
+
+var quotedNumber = regexp.MustCompile(`"(\d+)"`)
 
+// assignOut copies the values of OUT and INOUT parameters to their sql.Out destinations.
+func (s *stmt) assignOut() error {
+	s.outPending = false
+	for _, p := range s.params {
+		if p.inout != nil {
+			err := p.inout.convertAssign()
+			if err != nil {
+				return err
+			}
+
+		}
+		if p.array != nil && p.array.dest.IsValid() {
+			if err := p.array.assign(); err != nil {
+				return err
 			}
 		}
 	}
 	return nil
 }
 
+// drainResultSets closes the remaining result sets of a CALL
+// and assigns its OUT parameters.
+func (s *stmt) drainResultSets() error {
+	for {
+		ret := C.SQLMoreResults(C.SQLHSTMT(s.hstmt))
+		switch int(ret) {
+		case C.SQL_SUCCESS, C.SQL_SUCCESS_WITH_INFO:
+			continue
+		case C.SQL_NO_DATA_FOUND:
+			return s.assignOut()
+		default:
+			return formatError(C.SQL_HANDLE_STMT, s.hstmt)
+		}
+	}
+}
+
+// closeQuery discards the result set of a query run with Exec so
+// the statement can be executed again, and returns -1. For other
+// statements it returns the number of rows affected.
+func (s *stmt) closeQuery() (int64, error) {
+	var n C.SQLSMALLINT
+	ret := C.SQLNumResultCols(C.SQLHSTMT(s.hstmt), &n)
+	if !success(ret) {
+		return 0, formatError(C.SQL_HANDLE_STMT, s.hstmt)
+	}
+	if n < 1 {
+		return s.rowsAffected()
+	}
+	ret = C.SQLFreeStmt(C.SQLHSTMT(s.hstmt), C.SQL_CLOSE)
+	if !success(ret) {
+		return 0, formatError(C.SQL_HANDLE_STMT, s.hstmt)
+	}
+	return -1, nil
+}
+
+// prepareWarning reports whether ret is a warning that PrepareContext would have
+// accepted. With deferred prepare the statement is prepared by SQLExecute, so
+// the warning SQLSTATE 01504 (UPDATE or DELETE without a WHERE clause)
+// is returned from SQLExecute instead. Other warnings, such as data
+// truncation, are still returned.
+func (s *stmt) prepareWarning(ret C.SQLRETURN) bool {
+	if !s.conn.opts.DeferredPrepare || int(ret) != C.SQL_SUCCESS_WITH_INFO {
+		return false
+	}
+	states := diagSQLStates(C.SQL_HANDLE_STMT, s.hstmt)
+	for _, state := range states {
+		if state != "01504" {
+			return false
+		}
+	}
+	return len(states) > 0
+}
+
 func (s *stmt) rowsAffected() (int64, error) {
 	var c C.SQLLEN
 	ret := C.SQLRowCount(C.SQLHSTMT(s.hstmt), &c)
@@ -270,18 +488,13 @@ func (s *stmt) bindColumns() error {
 	// n < 1 indicates that the last statement or function executed
 	// did not generate a result set.
 	if n < 1 {
-		// It could be a update/insert/delete/merge statement
-		// and it is possible to get a number of affected row count
-		rowsAffected, err := s.rowsAffected()
-		if err != nil {
-			return err
-		}
-		return &rowsAffectedError{rowsAffected: rowsAffected}
+		s.cols = nil
+		return nil
 	}
 	// fetch column descriptions
 	s.cols = make([]*column, n)
 	for i := range s.cols {
-		c, err := newColumn(C.SQLHSTMT(s.hstmt), i)
+		c, err := newColumn(C.SQLHSTMT(s.hstmt), i, &s.conn.opts)
 		if err != nil {
 			return err
 		}
@@ -293,8 +506,10 @@ func (s *stmt) bindColumns() error {
 // [ -- driver.Rows ]
 type rows struct {
 	s *stmt
-	// Used to implement HasNextResultSet()
-	hasNextResultSet bool
+	// index of the current result set
+	set int
+	// true after SQLMoreResults returned SQL_NO_DATA_FOUND
+	done bool
 }
 
 func (r *rows) Columns() []string {
@@ -306,6 +521,12 @@ func (r *rows) Columns() []string {
 }
 
 func (r *rows) Close() error {
+	var err error
+	if r.s.outPending {
+		// skip the unread result sets of a CALL to get its OUT parameters
+		err = r.s.drainResultSets()
+	}
+
 	ret := C.SQLFreeStmt(C.SQLHSTMT(r.s.hstmt), C.SQL_UNBIND)
 	if !success(ret) {
 		return formatError(C.SQL_HANDLE_STMT, r.s.hstmt)
@@ -327,13 +548,16 @@ func (r *rows) Close() error {
 
 	r.s.rows = false
 	r.s = nil
-	return nil
+	return err
 }
 
 func (r *rows) Next(dest []driver.Value) error {
 	if r.s == nil {
 		return errors.New("database/sql/driver: [asifjalil][CLI Driver]: Next on closed Rows")
 	}
+	if len(r.s.cols) == 0 {
+		return io.EOF
+	}
 	ret := C.SQLFetch(C.SQLHSTMT(r.s.hstmt))
 	if ret == C.SQL_NO_DATA {
 		return io.EOF
@@ -373,17 +597,64 @@ func (r *rows) ColumnTypeScanType(index int) reflect.Type {
 	return r.s.cols[index].scanType()
 }
 
+// ColumnInfo returns the ColumnInfo of the column at index of the current
+// result set. database/sql doesn't give access to driver.Rows, so use
+// it on a statement prepared through sql.Conn.Raw:
+//
+//	err = conn.Raw(func(dc interface{}) error {
+//		st, err := dc.(driver.ConnPrepareContext).PrepareContext(ctx, "SELECT * FROM staff")
+//		if err != nil {
+//			return err
+//		}
+//		defer st.Close()
+//		rows, err := st.(driver.StmtQueryContext).QueryContext(ctx, nil)
+//		if err != nil {
+//			return err
+//		}
+//		defer rows.Close()
+//		info := rows.(interface{ ColumnInfo(int) cli.ColumnInfo }).ColumnInfo(0)
+//		...
+//	})
+func (r *rows) ColumnInfo(index int) ColumnInfo {
+	return r.s.cols[index].columnInfo()
+}
+
+// HasNextResultSet reports whether a CALL has result sets after the
+// current one. If DB2 didn't report the number of result sets,
+// it returns true until NextResultSet returns io.EOF.
 func (r *rows) HasNextResultSet() bool {
-	return r.hasNextResultSet
+	if r.done {
+		return false
+	}
+	if r.s.resultSets < 0 {
+		return true
+	}
+	return r.set+1 < r.s.resultSets
 }
 
+// NextResultSet moves to the next result set of a CALL and binds its columns.
+// After the last result set the OUT parameters of the CALL are assigned.
 func (r *rows) NextResultSet() error {
-	switch ret := C.SQLMoreResults(C.SQLHSTMT(r.s.hstmt)); ret {
-	case C.SQL_SUCCESS:
-		err := r.s.bindColumns()
-		return err
+	if r.done {
+		return io.EOF
+	}
+	ret := C.SQLMoreResults(C.SQLHSTMT(r.s.hstmt))
+	switch int(ret) {
+	case C.SQL_SUCCESS, C.SQL_SUCCESS_WITH_INFO:
+		ret = C.SQLFreeStmt(C.SQLHSTMT(r.s.hstmt), C.SQL_UNBIND)
+		if !success(ret) {
+			return formatError(C.SQL_HANDLE_STMT, r.s.hstmt)
+		}
+		r.s.cols = nil
+		r.set++
+		return r.s.bindColumns()
 	case C.SQL_NO_DATA_FOUND:
-		r.hasNextResultSet = false
+		r.done = true
+		if r.s.outPending {
+			if err := r.s.assignOut(); err != nil {
+				return err
+			}
+		}
 		return io.EOF
 	default:
 		return formatError(C.SQL_HANDLE_STMT, C.SQLHANDLE(r.s.hstmt))
@@ -392,12 +663,15 @@ func (r *rows) NextResultSet() error {
 
 // [ -- driver.Result --]
 type result struct {
-	id   int64
-	rows int64
+	id    int64
+	idErr error
+	rows  int64
 }
 
+// LastInsertId returns the identity value of a single-row INSERT when
+// the LastInsertID driver option is set.
 func (r *result) LastInsertId() (int64, error) {
-	return r.id, errors.New("not implemented")
+	return r.id, r.idErr
 }
 
 func (r *result) RowsAffected() (int64, error) {
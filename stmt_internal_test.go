@@ -0,0 +1,23 @@
+package cli
+
+import "testing"
+
+func TestResultSetCount(t *testing.T) {
+	tests := []struct {
+		err  cliError
+		want int
+	}{
+		{cliError{466, "0100C", `[IBM][CLI Driver][DB2/LINUXX8664] SQL0466W  The procedure "SCHEMA.PROC" returns "2" result sets.  SQLSTATE=0100C`}, 2},
+		{cliError{466, "0100C", `SQL0466W  Die Prozedur "SCHEMA.PROC" gibt "12" Ergebnismengen zurück.  SQLSTATE=0100C`}, 12},
+		{cliError{466, "0100C", `SQL0466W  The procedure "SCHEMA.PROC" returns result sets.`}, -1},
+		// a procedure name that is a number leaves the count ambiguous
+		{cliError{466, "0100C", `SQL0466W  The procedure "123" returns "3" result sets.`}, -1},
+		// a quoted number in another warning isn't a count
+		{cliError{462, "01H57", `SQL0462W  Routine "SCHEMA.PROC" (specific name "3") has returned a warning`}, -1},
+	}
+	for _, tc := range tests {
+		if got := resultSetCount(&tc.err); got != tc.want {
+			t.Errorf("%s: wanted %d, got %d", tc.err.message, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimestampStructTZ(t *testing.T) {
+	tests := []struct {
+		offset    int // seconds east of UTC
+		tzh, tzm  int
+		precision int
+		fraction  int // nanoseconds after truncation to precision
+	}{
+		{0, 0, 0, 9, 123456789},
+		{5*3600 + 45*60, 5, 45, 6, 123456000},
+		{-(3*3600 + 30*60), -3, 30, 3, 123000000},
+		{-30 * 60, 0, -30, 0, 0},
+	}
+	for _, tc := range tests {
+		in := time.Date(2009, time.November, 10, 23, 6, 29, 123456789, time.FixedZone("", tc.offset))
+		ts := timestampStructTZ(in, tc.precision)
+		if int(ts.year) != 2009 || int(ts.month) != 11 || int(ts.day) != 10 ||
+			int(ts.hour) != 23 || int(ts.minute) != 6 || int(ts.second) != 29 {
+			t.Errorf("offset %d: wanted the local date and time of %v, got %+v", tc.offset, in, ts)
+		}
+		if int(ts.fraction) != tc.fraction {
+			t.Errorf("offset %d: wanted fraction %d, got %d", tc.offset, tc.fraction, ts.fraction)
+		}
+		if int(ts.timezone_hour) != tc.tzh || int(ts.timezone_minute) != tc.tzm {
+			t.Errorf("offset %d: wanted time zone %d:%d, got %d:%d",
+				tc.offset, tc.tzh, tc.tzm, ts.timezone_hour, ts.timezone_minute)
+		}
+
+		// timeFromTZ reverses timestampStructTZ
+		want := in.Add(time.Duration(tc.fraction - in.Nanosecond()))
+		got := timeFromTZ(&ts)
+		if !got.Equal(want) {
+			t.Errorf("offset %d: wanted %v, got %v", tc.offset, want, got)
+		}
+		if _, offset := got.Zone(); offset != tc.offset {
+			t.Errorf("offset %d: wanted offset %d, got %d", tc.offset, tc.offset, offset)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The following types wrap a Go value to bind it to a parameter marker
+// as a specific DB2 SQL type. Without a wrapper the SQL type is picked
+// from the Go type: string binds as WCHAR (VARCHAR with the UTF8 option),
+// []byte as BINARY, and time.Time as TIMESTAMP.
+//
+// Example:
+//
+//	db.Exec("INSERT INTO emp_resume(empno, resume_format, resume) VALUES(?, ?, ?)",
+//		"000140", "ascii", cli.CLOB(resume))
+
+// Time binds the time of day of a time.Time as a TIME value. The time.Time
+// is converted to the Location driver option first.
+// DB2 TIME has no fractional seconds.
+type Time time.Time
+
+// Timestamp binds a time.Time as a TIMESTAMP(Precision) value.
+// Precision is the number of fractional second digits, from 0 to 12.
+type Timestamp struct {
+	Time      time.Time
+	Precision int
+}
+
+// ExtTimestamp is a TIMESTAMP(12) value. Time holds the value up to nanoseconds
+// and Picosecond the three digits after them, 0 to 999.
+//
+// A TIMESTAMP(10) to TIMESTAMP(12) column is returned as an ExtTimestamp in
+// the Location driver option; scan it into an ExtTimestamp, not a time.Time.
+type ExtTimestamp struct {
+	Time       time.Time
+	Picosecond int
+}
+
+// Scan implements sql.Scanner. It accepts an ExtTimestamp or a time.Time.
+// A string has no time zone, and a Scanner doesn't know the Location of the
+// connection, so Scan doesn't accept a string; Cursor.Scan, OUT parameters
+// and OUT ARRAY elements parse a string or []byte in the DB2 format
+// yyyy-mm-dd-hh.mm.ss.ffffffffffff in the Location driver option.
+func (ts *ExtTimestamp) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case ExtTimestamp:
+		*ts = v
+		return nil
+	case time.Time:
+		*ts = ExtTimestamp{Time: v}
+		return nil
+	}
+	return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: cannot scan %T into cli.ExtTimestamp", src)
+}
+
+// scanIn is Scan that also accepts a string parsed in loc.
+func (ts *ExtTimestamp) scanIn(src interface{}, loc *time.Location) error {
+	switch v := src.(type) {
+	case ExtTimestamp:
+		*ts = v
+		return nil
+	case time.Time:
+		*ts = ExtTimestamp{Time: v}
+		return nil
+	case []byte:
+		return ts.parse(string(v), loc)
+	case string:
+		return ts.parse(v, loc)
+	}
+	return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: cannot scan %T into cli.ExtTimestamp", src)
+}
+
+func (ts *ExtTimestamp) parse(s string, loc *time.Location) error {
+	s = strings.TrimSpace(s)
+	var pico int
+	// yyyy-mm-dd-hh.mm.ss.nnnnnnnnnppp
+	// time.Parse handles the fraction up to nanoseconds.
+	if len(s) > 29 {
+		p, err := strconv.Atoi((s[29:] + "00")[:3])
+		if err != nil {
+			return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: invalid timestamp %q", s)
+		}
+		pico = p
+		s = s[:29]
+	}
+	t, err := time.ParseInLocation("2006-01-02-15.04.05.999999999", s, loc)
+	if err != nil {
+		return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: invalid timestamp %q", s)
+	}
+	*ts = ExtTimestamp{Time: t, Picosecond: pico}
+	return nil
+}
+
+// CLOB binds a string as a CLOB value.
+type CLOB string
+
+// BLOB binds a byte slice as a BLOB value.
+type BLOB []byte
+
+// XML binds a UTF-8 encoded XML document as an XML value.
+type XML string
+
+// Graphic binds a string as a GRAPHIC value.
+type Graphic string
+
+// Char binds a string as a CHAR value.
+type Char string
+
+// Decimal binds the decimal number in a string, for example "-1234.50",
+// as a DECIMAL value with the precision and scale of the number.
+type Decimal string
@@ -13,6 +13,8 @@ func sqlTypeToCType(sqltype C.SQLSMALLINT) C.SQLSMALLINT {
 	switch sqltype {
 	case C.SQL_BIGINT:
 		ctype = C.SQL_C_SBIGINT
+	case C.SQL_BOOLEAN:
+		ctype = C.SQL_C_BIT
 	case C.SQL_BLOB,
 		C.SQL_BINARY,
 		C.SQL_BIT,
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+)
+
+// UUID is a 16 byte universally unique identifier stored in a
+// CHAR(16) FOR BIT DATA column. A UUID parameter is bound as
+// binary data, so DB2 doesn't convert it to another code page.
+type UUID [16]byte
+
+// ParseUUID parses s in the format xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx,
+// or as 32 hexadecimal digits without hyphens.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) == 36 && s[8] == '-' && s[13] == '-' && s[18] == '-' && s[23] == '-' {
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(s) != 32 {
+		return u, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: invalid UUID %q", s)
+	}
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return u, fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: invalid UUID %q", s)
+	}
+	return u, nil
+}
+
+// String returns u in the format xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
+func (u UUID) String() string {
+	h := hex.EncodeToString(u[:])
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// Value implements driver.Valuer.
+func (u UUID) Value() (driver.Value, error) {
+	return u[:], nil
+}
+
+// Scan implements sql.Scanner. It accepts the 16 bytes of a CHAR(16) FOR BIT DATA
+// value or a string in the format accepted by ParseUUID.
+func (u *UUID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		if len(v) != len(u) {
+			return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: cannot scan %d bytes into cli.UUID", len(v))
+		}
+		copy(u[:], v)
+		return nil
+	case string:
+		p, err := ParseUUID(v)
+		if err != nil {
+			return err
+		}
+		*u = p
+		return nil
+	}
+	return fmt.Errorf("database/sql/driver: [asifjalil][CLI Driver]: cannot scan %T into cli.UUID", src)
+}